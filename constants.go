@@ -4,6 +4,22 @@ import "fmt"
 
 var (
 	ErrRequiredParameterMissing   = fmt.Errorf("echopen: required parameter missing")
+	ErrParameterInvalid           = fmt.Errorf("echopen: parameter value is invalid")
+	ErrValueNotBound              = fmt.Errorf("echopen: value not bound to request")
+	ErrValueTypeMismatch          = fmt.Errorf("echopen: bound value has a different type")
 	ErrSecurityRequirementsNotMet = fmt.Errorf("echopen: at least one required security scheme must be provided")
 	ErrContentTypeNotSupported    = fmt.Errorf("echopen: request did not match defined content types")
+	ErrRequestTooLarge            = fmt.Errorf("echopen: request exceeds the size limit")
+	ErrRequestBodyMissing         = fmt.Errorf("echopen: required request body missing")
+	ErrRequestBodyInvalid         = fmt.Errorf("echopen: request body is invalid")
+	ErrInsufficientScope          = fmt.Errorf("echopen: granted scopes do not cover the security requirement")
+	ErrTokenMalformed             = fmt.Errorf("echopen: token is malformed")
+	ErrTokenAlgorithmUnsupported  = fmt.Errorf("echopen: token signing algorithm not supported")
+	ErrTokenSignatureInvalid      = fmt.Errorf("echopen: token signature is invalid")
+	ErrTokenExpired               = fmt.Errorf("echopen: token is expired")
+	ErrTokenNotYetValid           = fmt.Errorf("echopen: token is not valid yet")
+	ErrTokenIssuerInvalid         = fmt.Errorf("echopen: token issuer is invalid")
+	ErrTokenAudienceInvalid       = fmt.Errorf("echopen: token audience is invalid")
+	ErrSignatureInvalid           = fmt.Errorf("echopen: request signature is invalid")
+	ErrSignatureExpired           = fmt.Errorf("echopen: request signature timestamp outside tolerance")
 )
@@ -98,22 +98,25 @@ func hello(c echo.Context) error {
 }
 
 func helloID(c echo.Context) error {
-	id := c.Get("param.id").(string)
-	return c.String(http.StatusOK, "Hello, World! - "+id)
+	id, err := echopen.PathParam[int](c, "id")
+	if err != nil {
+		return err
+	}
+	return c.String(http.StatusOK, fmt.Sprintf("Hello, World! - %d", id))
 }
 
 func helloQuery(c echo.Context) error {
-	qry := c.Get("query").(*QueryParams)
-	if qry == nil {
-		return echo.ErrInternalServerError
+	qry, err := echopen.Query[*QueryParams](c)
+	if err != nil {
+		return err
 	}
 	return c.String(http.StatusOK, fmt.Sprintf("Hello, World! - %#v", qry))
 }
 
 func helloBody(c echo.Context) error {
-	body := c.Get("body").(*RequestBody)
-	if body == nil {
-		return echo.ErrInternalServerError
+	body, err := echopen.Body[*RequestBody](c)
+	if err != nil {
+		return err
 	}
 	return c.String(http.StatusOK, fmt.Sprintf("Hello, World! - %#v", body))
 }
@@ -65,6 +65,7 @@ func main() {
 		newTodo,
 		echopen.WithDescription("Create a new Todo"),
 		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "New Todo", NewTodo{}),
+		echopen.WithRequestBodyRequired(true),
 		echopen.WithResponseStruct(fmt.Sprint(http.StatusCreated), "Successful response", Todo{}),
 		echopen.WithResponseRef(fmt.Sprint(http.StatusBadRequest), "BadRequestResponse"),
 		echopen.WithResponseRef("default", "UnexpectedErrorResponse"),
@@ -85,6 +86,7 @@ func main() {
 		echopen.WithDescription("Update a todo"),
 		echopen.WithPathParameter("id", "Todo ID", uuid.Must(uuid.FromString("11c7810d-6627-497a-91e9-e3dc4812ce30"))),
 		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "Updated Todo", UpdateTodo{}),
+		echopen.WithRequestBodyRequired(true),
 		echopen.WithResponseStruct(fmt.Sprint(http.StatusOK), "Successful response", Todo{}),
 		echopen.WithResponseRef(fmt.Sprint(http.StatusBadRequest), "BadRequestResponse"),
 		echopen.WithResponseRef("default", "UnexpectedErrorResponse"),
@@ -133,7 +135,10 @@ func getTodos(c echo.Context) error {
 }
 
 func newTodo(c echo.Context) error {
-	body := c.Get("body").(*NewTodo)
+	body, err := echopen.Body[*NewTodo](c)
+	if err != nil {
+		return err
+	}
 
 	id, err := uuid.NewV4()
 	if err != nil {
@@ -158,7 +163,10 @@ func newTodo(c echo.Context) error {
 }
 
 func getTodoByID(c echo.Context) error {
-	id := c.Get("path.id").(uuid.UUID)
+	id, err := echopen.PathParam[uuid.UUID](c, "id")
+	if err != nil {
+		return err
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -170,8 +178,14 @@ func getTodoByID(c echo.Context) error {
 }
 
 func updateTodo(c echo.Context) error {
-	id := c.Get("path.id").(uuid.UUID)
-	body := c.Get("body").(*UpdateTodo)
+	id, err := echopen.PathParam[uuid.UUID](c, "id")
+	if err != nil {
+		return err
+	}
+	body, err := echopen.Body[*UpdateTodo](c)
+	if err != nil {
+		return err
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -191,7 +205,10 @@ func updateTodo(c echo.Context) error {
 }
 
 func deleteTodo(c echo.Context) error {
-	id := c.Get("path.id").(uuid.UUID)
+	id, err := echopen.PathParam[uuid.UUID](c, "id")
+	if err != nil {
+		return err
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
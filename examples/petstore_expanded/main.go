@@ -60,6 +60,16 @@ func main() {
 		},
 	})
 
+	// Scoped requirements need a validator to grant scopes, accept the sample key as a token with full access
+	api.SetSecurityValidator("petstore_auth", echopen.SecurityValidatorFunc(
+		func(c echo.Context, cred *echopen.Credential) (interface{}, []string, error) {
+			if cred.Value != "special-key" {
+				return nil, nil, echo.ErrUnauthorized
+			}
+			return cred.Value, []string{"write:pets", "read:pets"}, nil
+		},
+	))
+
 	api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
 		Type: v320.APIKeySecuritySchemeType,
 		Name: "api_key",
@@ -20,16 +20,11 @@ func main() {
 		"1.0.0",
 		echopen.WithSpecDescription("Demonstration of routes with security requirements"),
 		echopen.WithSpecLicense(&v320.License{Name: "MIT", URL: "https://example.com/license"}),
+		echopen.WithAPIKeyScheme("api_key", "header", "X-API-Key"),
 	)
 
 	api.Spec.GetComponents().AddJSONResponse("ErrorResponse", "Error response", api.ToSchemaRef(ErrorResponseBody{}))
 
-	api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
-		Type: v320.APIKeySecuritySchemeType,
-		In:   "header",
-		Name: "X-API-Key",
-	})
-
 	// Optional security route
 	api.GET(
 		"/hello",
@@ -61,15 +56,26 @@ func main() {
 }
 
 func hello(c echo.Context) error {
+	// Security is optional, so the key may not be present
+	auth, ok := echopen.SecurityValue(c, "api_key")
+	if !ok {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"authenticated": false,
+		})
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"value":  c.Get("security.api_key"),
-		"scopes": c.Get("security.api_key.scopes"),
+		"authenticated": true,
+		"value":         auth.Credential.Value,
+		"scopes":        auth.Scopes,
 	})
 }
 
 func helloSecure(c echo.Context) error {
+	auth, _ := echopen.SecurityValue(c, "api_key")
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"value":  c.Get("security.api_key"),
-		"scopes": c.Get("security.api_key.scopes"),
+		"value":  auth.Credential.Value,
+		"scopes": auth.Scopes,
 	})
 }
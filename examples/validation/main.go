@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -44,6 +45,7 @@ func main() {
 		"/validate",
 		validate,
 		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "Request parameters", Request{}),
+		echopen.WithRequestBodyRequired(true),
 		echopen.WithResponseStruct(fmt.Sprint(http.StatusOK), "Successful response", Response{}),
 		echopen.WithResponseStruct("default", "Error response", Error{}),
 	)
@@ -60,7 +62,10 @@ func main() {
 }
 
 func validate(c echo.Context) error {
-	body := c.Get("body").(*Request)
+	body, err := echopen.Body[*Request](c)
+	if err != nil {
+		return err
+	}
 	return c.JSON(http.StatusOK, &Response{
 		StringLen: body.StringLen,
 		NumRange:  body.NumRange,
@@ -70,7 +75,8 @@ func validate(c echo.Context) error {
 func onError(err error, c echo.Context) {
 	var err2 error
 
-	if ve, ok := err.(validator.ValidationErrors); ok {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
 		// Validation error - send a 400 with the first error
 		err2 = c.JSON(http.StatusBadRequest, Error{
 			Code:    "bad_request",
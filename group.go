@@ -98,9 +98,7 @@ func (g *GroupWrapper) Add(method string, path string, handler echo.HandlerFunc,
 	for parentGroup != nil {
 		wrapper = WithTags(parentGroup.Tags...)(wrapper)
 		for _, req := range parentGroup.SecurityRequirements {
-			for name, scopes := range *req {
-				wrapper = WithSecurityRequirement(name, scopes)(wrapper)
-			}
+			wrapper = WithSecurityRequirementObject(req)(wrapper)
 		}
 		parentGroup = parentGroup.GroupWrapper
 	}
@@ -113,6 +111,7 @@ func (g *GroupWrapper) Add(method string, path string, handler echo.HandlerFunc,
 	// Add validation middleware to the start of the chain
 	middlewares := []echo.MiddlewareFunc{}
 	if !g.API.Config.DisableDefaultMiddleware {
+		wrapper.checkSecurityValidators()
 		middlewares = append(middlewares, wrapper.middleware())
 	}
 	middlewares = append(middlewares, wrapper.Middlewares...)
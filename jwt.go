@@ -0,0 +1,427 @@
+package echopen
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JSONWebKey is a single key within a JSON Web Key Set document (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC and OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// Symmetric
+	K string `json:"k,omitempty"`
+}
+
+// JWKS is a set of keys used to verify JWT signatures
+type JWKS struct {
+	keys []*jwksKey
+}
+
+type jwksKey struct {
+	kid string
+	alg string
+	key interface{}
+}
+
+// NewJWKS creates an empty key set, to be populated with AddKey
+func NewJWKS() *JWKS {
+	return &JWKS{}
+}
+
+// ParseJWKS parses a JSON Web Key Set document.
+// Keys with a use other than "sig" are skipped.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	doc := struct {
+		Keys []*JSONWebKey `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("echopen: invalid JWKS: %w", err)
+	}
+
+	s := NewJWKS()
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		s.AddKey(k.Kid, k.Alg, key)
+	}
+
+	return s, nil
+}
+
+// LoadJWKSFile reads and parses a JSON Web Key Set document from disk
+func LoadJWKSFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// FetchJWKS retrieves and parses a JSON Web Key Set document from a URL
+func FetchJWKS(ctx context.Context, url string) (*JWKS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("echopen: fetching JWKS from %s: unexpected status %d", url, res.StatusCode)
+	}
+
+	doc := json.RawMessage{}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("echopen: invalid JWKS: %w", err)
+	}
+	return ParseJWKS(doc)
+}
+
+// AddKey adds a verification key to the set.
+// Key must be one of *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte (HMAC secret).
+// An empty alg allows the key to be used with any algorithm matching the key type.
+func (s *JWKS) AddKey(kid string, alg string, key interface{}) {
+	s.keys = append(s.keys, &jwksKey{kid: kid, alg: alg, key: key})
+}
+
+// lookup returns candidate keys for a token header
+func (s *JWKS) lookup(kid string, alg string) []interface{} {
+	keys := []interface{}{}
+	for _, k := range s.keys {
+		if kid != "" && k.kid != "" && k.kid != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		keys = append(keys, k.key)
+	}
+	return keys
+}
+
+// PublicKey converts the JSON representation into a key usable for verification
+func (k *JSONWebKey) PublicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("echopen: invalid JWK modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("echopen: invalid JWK exponent: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("echopen: invalid JWK x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("echopen: invalid JWK y coordinate: %w", err)
+		}
+
+		var curve ecdh.Curve
+		var ecCurve elliptic.Curve
+		var size int
+		switch k.Crv {
+		case "P-256":
+			curve, ecCurve, size = ecdh.P256(), elliptic.P256(), 32
+		case "P-384":
+			curve, ecCurve, size = ecdh.P384(), elliptic.P384(), 48
+		case "P-521":
+			curve, ecCurve, size = ecdh.P521(), elliptic.P521(), 66
+		default:
+			return nil, fmt.Errorf("echopen: unsupported JWK curve %s", k.Crv)
+		}
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("echopen: invalid JWK coordinate length for %s", k.Crv)
+		}
+
+		// Parsing the uncompressed point checks it lies on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := curve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("echopen: invalid JWK point: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: ecCurve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("echopen: unsupported JWK curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("echopen: invalid JWK Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("echopen: invalid JWK secret: %w", err)
+		}
+		return secret, nil
+
+	default:
+		return nil, fmt.Errorf("echopen: unsupported JWK key type %s", k.Kty)
+	}
+}
+
+// JWTClaims holds the claims of a validated token
+type JWTClaims map[string]interface{}
+
+// Subject returns the sub claim
+func (c JWTClaims) Subject() string {
+	s, _ := c["sub"].(string)
+	return s
+}
+
+// Scopes returns the scopes granted by the scope (space separated) or scp (array or space separated) claims
+func (c JWTClaims) Scopes() []string {
+	scopes := []string{}
+	for _, claim := range []string{"scope", "scp"} {
+		switch v := c[claim].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(v)...)
+		case []interface{}:
+			for _, s := range v {
+				if str, ok := s.(string); ok {
+					scopes = append(scopes, str)
+				}
+			}
+		}
+	}
+	return scopes
+}
+
+// JWTValidator is a SecurityValidator for bearer JWTs, typically used with oauth2 and openIdConnect schemes.
+// The validated JWTClaims are returned as the principal, and the scope/scp claims as the granted scopes.
+type JWTValidator struct {
+	// Keys used to verify token signatures
+	Keys *JWKS
+	// Expected iss claim, not checked if empty
+	Issuer string
+	// Accepted aud claim values, at least one must be present in the token if set
+	Audience []string
+	// Allowed clock skew when checking exp and nbf
+	Leeway time.Duration
+	// Clock used for time based checks, defaults to time.Now
+	Now func() time.Time
+}
+
+func (v *JWTValidator) Validate(c echo.Context, cred *Credential) (interface{}, []string, error) {
+	claims, err := v.ValidateToken(cred.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	return claims, claims.Scopes(), nil
+}
+
+// ValidateToken verifies the token signature and registered claims, returning the token claims
+func (v *JWTValidator) ValidateToken(token string) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	if v.Keys == nil {
+		return nil, ErrTokenSignatureInvalid
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range v.Keys.lookup(header.Kid, header.Alg) {
+		ok, err := verifyJWTSignature(header.Alg, key, signed, sig)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrTokenSignatureInvalid
+	}
+
+	claims := JWTClaims{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	if exp, ok := claims["exp"].(float64); ok {
+		if now.After(time.Unix(int64(exp), 0).Add(v.Leeway)) {
+			return nil, ErrTokenExpired
+		}
+	}
+	if nbf, ok := claims["nbf"].(float64); ok {
+		if now.Before(time.Unix(int64(nbf), 0).Add(-v.Leeway)) {
+			return nil, ErrTokenNotYetValid
+		}
+	}
+
+	if v.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.Issuer {
+			return nil, ErrTokenIssuerInvalid
+		}
+	}
+
+	if len(v.Audience) > 0 {
+		aud := []string{}
+		switch a := claims["aud"].(type) {
+		case string:
+			aud = append(aud, a)
+		case []interface{}:
+			for _, s := range a {
+				if str, ok := s.(string); ok {
+					aud = append(aud, str)
+				}
+			}
+		}
+
+		matched := false
+		for _, want := range v.Audience {
+			for _, got := range aud {
+				if want == got {
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return nil, ErrTokenAudienceInvalid
+		}
+	}
+
+	return claims, nil
+}
+
+func decodeJWTSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// verifyJWTSignature checks a JWS signature for the given algorithm.
+// Keys of the wrong type for the algorithm do not match, rather than returning an error.
+func verifyJWTSignature(alg string, key interface{}, signed []byte, sig []byte) (bool, error) {
+	var h crypto.Hash
+	var newHash func() hash.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256", "HS256":
+		h, newHash = crypto.SHA256, sha256.New
+	case "RS384", "PS384", "ES384", "HS384":
+		h, newHash = crypto.SHA384, sha512.New384
+	case "RS512", "PS512", "ES512", "HS512":
+		h, newHash = crypto.SHA512, sha512.New
+	case "EdDSA":
+		k, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(k, signed, sig), nil
+	default:
+		return false, ErrTokenAlgorithmUnsupported
+	}
+
+	hasher := newHash()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		k, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(k, h, digest, sig) == nil, nil
+	case "PS":
+		k, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPSS(k, h, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil, nil
+	case "ES":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return false, nil
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false, nil
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s), nil
+	case "HS":
+		k, ok := key.([]byte)
+		if !ok {
+			return false, nil
+		}
+		mac := hmac.New(newHash, k)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), sig), nil
+	}
+
+	return false, ErrTokenAlgorithmUnsupported
+}
@@ -0,0 +1,165 @@
+package echopen_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func signJWT(t *testing.T, alg string, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]interface{}{"alg": alg, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		s, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.NoError(t, err)
+		sig = s
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		assert.NoError(t, err)
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWTValidator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]interface{}{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, jwks, 0o600))
+
+	keys, err := echopen.LoadJWKSFile(path)
+	assert.NoError(t, err)
+
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithSecurityValidator("oauth", &echopen.JWTValidator{
+			Keys:     keys,
+			Issuer:   "https://issuer.example.com",
+			Audience: []string{"api"},
+		}),
+	)
+	api.Spec.GetComponents().AddSecurityScheme("oauth", &v320.SecurityScheme{
+		Type: v320.OAuth2SecuritySchemeType,
+		Flows: &v320.OAuthFlows{
+			ClientCredentials: &v320.OAuthFlow{
+				TokenURL: "https://issuer.example.com/token",
+				Scopes:   map[string]string{"read": "Read access"},
+			},
+		},
+	})
+	api.GET("/", func(c echo.Context) error {
+		claims := c.Get("security.oauth.principal").(echopen.JWTClaims)
+		assert.Equal(t, "user-1", claims.Subject())
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("oauth", []string{"read"}))
+
+	now := time.Now().Unix()
+	valid := map[string]interface{}{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"api"},
+		"exp":   now + 60,
+		"nbf":   now - 60,
+		"scope": "read write",
+	}
+	with := func(k string, v interface{}) map[string]interface{} {
+		claims := map[string]interface{}{}
+		for ck, cv := range valid {
+			claims[ck] = cv
+		}
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+		return claims
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	type tcd struct {
+		Name     string
+		Token    string
+		Expected int
+	}
+
+	defs := []tcd{
+		{"valid", signJWT(t, "RS256", "key-1", key, valid), http.StatusOK},
+		{"no_scope", signJWT(t, "RS256", "key-1", key, with("scope", nil)), http.StatusForbidden},
+		{"scp_array", signJWT(t, "RS256", "key-1", key, with("scp", []string{"read"})), http.StatusOK},
+		{"expired", signJWT(t, "RS256", "key-1", key, with("exp", now-60)), http.StatusUnauthorized},
+		{"not_yet_valid", signJWT(t, "RS256", "key-1", key, with("nbf", now+60)), http.StatusUnauthorized},
+		{"wrong_issuer", signJWT(t, "RS256", "key-1", key, with("iss", "https://other.example.com")), http.StatusUnauthorized},
+		{"wrong_audience", signJWT(t, "RS256", "key-1", key, with("aud", "other")), http.StatusUnauthorized},
+		{"wrong_key", signJWT(t, "RS256", "key-1", otherKey, valid), http.StatusUnauthorized},
+		{"malformed", "not-a-token", http.StatusUnauthorized},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.Token)
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+}
+
+func TestJWTValidatorInMemoryKeys(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	keys := echopen.NewJWKS()
+	keys.AddKey("ec-1", "ES256", &key.PublicKey)
+
+	v := &echopen.JWTValidator{Keys: keys}
+
+	claims, err := v.ValidateToken(signJWT(t, "ES256", "ec-1", key, map[string]interface{}{"sub": "user-2", "scp": "a b"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "user-2", claims.Subject())
+	assert.Equal(t, []string{"a", "b"}, claims.Scopes())
+
+	_, err = v.ValidateToken(signJWT(t, "ES256", "ec-2", key, map[string]interface{}{"sub": "user-2"}))
+	assert.ErrorIs(t, err, echopen.ErrTokenSignatureInvalid)
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + "."
+	_, err = v.ValidateToken(none)
+	assert.Error(t, err)
+}
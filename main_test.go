@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -172,6 +173,75 @@ func TestRouteParamCookieToken(t *testing.T) {
 	fmt.Println(req.Body)
 }
 
+func TestRouteParamOptional(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			assert.Nil(t, c.Get("header.X-Trace"))
+			assert.Nil(t, c.Get("cookie.session_token"))
+			assert.Equal(t, 20, c.Get("query.limit"))
+			assert.Equal(t, "en", c.Get("header.Accept-Language"))
+			assert.Equal(t, []interface{}{"a", "b"}, c.Get("cookie.tags"))
+			return c.NoContent(204)
+		},
+		echopen.WithHeaderParameter("X-Trace", "Trace ID", ""),
+		echopen.WithCookieParameter("session_token", "Session Token", ""),
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name:   "limit",
+			Schema: &v320.Schema{Type: v320.IntegerSchemaType, Default: 20},
+		}),
+		echopen.WithHeaderParameterConfig(&echopen.HeaderParameterConfig{
+			Name:   "Accept-Language",
+			Schema: &v320.Schema{Type: v320.StringSchemaType, Default: "en"},
+		}),
+		echopen.WithCookieParameterConfig(&echopen.CookieParameterConfig{
+			Name: "tags",
+			Schema: &v320.Schema{
+				Type:    v320.ArraySchemaType,
+				Items:   &v320.Ref[v320.Schema]{Value: &v320.Schema{Type: v320.StringSchemaType}},
+				Default: "a,b",
+			},
+		}),
+	)
+
+	_, res := executeRequest(api, http.MethodGet, "/", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	api.GET(
+		"/required",
+		func(c echo.Context) error {
+			return c.NoContent(204)
+		},
+		echopen.WithHeaderParameterConfig(&echopen.HeaderParameterConfig{Name: "X-Trace", Required: true}),
+	)
+
+	_, res = executeRequest(api, http.MethodGet, "/required", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
+func TestRouteHeaderStructDefault(t *testing.T) {
+	type HeaderStruct struct {
+		Limit int    `header:"X-Limit" default:"10"`
+		Trace string `header:"X-Trace"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			hdr := c.Get("header").(*HeaderStruct)
+			assert.Equal(t, 10, hdr.Limit)
+			assert.Equal(t, "", hdr.Trace)
+			return c.NoContent(204)
+		},
+		echopen.WithHeaderStruct(HeaderStruct{}),
+	)
+
+	_, res := executeRequest(api, http.MethodGet, "/", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+}
+
 func TestRouteParamEmptyExample(t *testing.T) {
 	api := echopen.New("Test", "1.0.0")
 	api.GET(
@@ -209,6 +279,170 @@ func TestRouteQueryStruct(t *testing.T) {
 	assert.Equal(t, 204, res.Result().StatusCode)
 }
 
+func TestRouteQueryStructParameters(t *testing.T) {
+	type QueryStruct struct {
+		Search string  `query:"search" validate:"required,max=20" pattern:"^[a-z]+$" example:"foo"`
+		Limit  int32   `query:"limit" validate:"min=1,max=100" default:"20"`
+		Tags   []int64 `query:"tags"`
+		Page   int     `query:"page" deprecated:"true"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			return c.NoContent(204)
+		},
+		echopen.WithQueryStruct(QueryStruct{}),
+	)
+
+	params := api.Spec.Paths["/"].Value.Get.Parameters
+	assert.Len(t, params, 4)
+
+	names := []string{}
+	for _, p := range params {
+		names = append(names, p.Value.Name)
+	}
+	assert.Equal(t, []string{"search", "limit", "tags", "page"}, names)
+
+	search := params[0].Value
+	assert.True(t, search.Required)
+	assert.Equal(t, "^[a-z]+$", search.Schema.Pattern)
+	assert.Equal(t, 20, *search.Schema.MaxLength)
+	assert.Equal(t, []interface{}{"foo"}, search.Schema.Examples)
+
+	limit := params[1].Value
+	assert.False(t, limit.Required)
+	assert.Equal(t, v320.SchemaFormat("int32"), limit.Schema.Format)
+	assert.Equal(t, 1.0, *limit.Schema.Minimum)
+	assert.Equal(t, 100.0, *limit.Schema.Maximum)
+	assert.Equal(t, "20", limit.Schema.Default)
+
+	tags := params[2].Value
+	assert.Equal(t, v320.SchemaFormat("int64"), tags.Schema.Items.Value.Format)
+
+	assert.True(t, params[3].Value.Deprecated)
+	assert.True(t, params[3].Value.Schema.Deprecated)
+
+	_, res := executeRequest(api, http.MethodGet, "/?search=foo&limit=20", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+}
+
+func TestRouteQueryStructSchemaKeywords(t *testing.T) {
+	type QueryStruct struct {
+		Code string `query:"code" pattern:"^[a-z]+$"`
+		Kind string `query:"kind" enum:"a,b"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			return c.NoContent(204)
+		},
+		echopen.WithQueryStruct(QueryStruct{}),
+	)
+
+	type tcd struct {
+		Target   string
+		Expected int
+		Keyword  string
+	}
+
+	defs := []tcd{
+		{"/?code=abc&kind=a", 204, ""},
+		{"/", 204, ""},
+		{"/?code=123&kind=a", 400, "pattern"},
+		{"/?code=abc&kind=zzz", 400, "enum"},
+	}
+
+	for _, d := range defs {
+		t.Run(d.Target, func(t *testing.T) {
+			_, res := executeRequest(api, http.MethodGet, d.Target, nil)
+			assert.Equal(t, d.Expected, res.Result().StatusCode)
+			if d.Keyword != "" {
+				assert.Contains(t, res.Body.String(), fmt.Sprintf(`"keyword":"%s"`, d.Keyword))
+			}
+		})
+	}
+}
+
+func TestRouteQueryStructEmbedded(t *testing.T) {
+	type Pagination struct {
+		Limit  int `query:"limit" validate:"max=100"`
+		Offset int `query:"offset"`
+	}
+	type Sorting struct {
+		Sort string `query:"sort"`
+	}
+	type QueryStruct struct {
+		Search string `query:"search"`
+		Pagination
+		*Sorting
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			q, err := echopen.Query[*QueryStruct](c)
+			assert.NoError(t, err)
+			assert.Equal(t, "foo", q.Search)
+			assert.Equal(t, 10, q.Limit)
+			assert.Equal(t, 20, q.Offset)
+			if assert.NotNil(t, q.Sorting) {
+				assert.Equal(t, "name", q.Sort)
+			}
+			return c.NoContent(204)
+		},
+		echopen.WithQueryStruct(QueryStruct{}),
+	)
+
+	names := []string{}
+	for _, p := range api.Spec.Paths["/"].Value.Get.Parameters {
+		names = append(names, p.Value.Name)
+	}
+	assert.Equal(t, []string{"search", "limit", "offset", "sort"}, names)
+
+	_, res := executeRequest(api, http.MethodGet, "/?search=foo&limit=10&offset=20&sort=name", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/?search=foo&limit=1000", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
+func TestRouteQueryParameter(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			assert.Equal(t, 100, c.Get("query.limit"))
+			assert.Equal(t, []interface{}{int32(1), int32(2)}, c.Get("query.ids"))
+			assert.Nil(t, c.Get("query.search"))
+			return c.NoContent(204)
+		},
+		echopen.WithQueryParameter("limit", "Limit", int(0)),
+		echopen.WithQueryParameter("search", "Search", ""),
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name:     "ids",
+			Required: true,
+			Schema:   api.TypeToSchema(reflect.TypeOf([]int32{})),
+		}),
+	)
+
+	_, res := executeRequest(api, http.MethodGet, "/?limit=100&ids=1&ids=2", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/?limit=100", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/?limit=abc&ids=1", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/?ids=1&ids=x", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
 func TestNestedGroup(t *testing.T) {
 	api := echopen.New("Test", "1.0.0")
 
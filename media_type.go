@@ -0,0 +1,127 @@
+package echopen
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// mediaRange is a parsed media type, or a media range using wildcards such as image/* or */*
+type mediaRange struct {
+	Type    string
+	Subtype string
+	Params  map[string]string
+}
+
+func parseMediaRange(s string) (mediaRange, bool) {
+	mt, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return mediaRange{}, false
+	}
+	typ, sub, ok := strings.Cut(mt, "/")
+	if !ok || typ == "" || sub == "" {
+		return mediaRange{}, false
+	}
+	return mediaRange{Type: typ, Subtype: sub, Params: params}, true
+}
+
+func (m mediaRange) String() string {
+	return m.Type + "/" + m.Subtype
+}
+
+// suffix returns the structured syntax suffix of the subtype, such as json for application/vnd.api+json
+func (m mediaRange) suffix() string {
+	if i := strings.LastIndex(m.Subtype, "+"); i >= 0 {
+		return m.Subtype[i+1:]
+	}
+	return ""
+}
+
+// matchScore returns the specificity with which the range matches a media type, or zero if it does not match.
+// Exact matches rank above structured suffix matches, such as application/json for application/merge-patch+json, followed by type/* and */*.
+// Parameters of the range must be present in the media type with the same value, and each one adds to the specificity.
+func (r mediaRange) matchScore(m mediaRange) int {
+	var rank int
+	switch {
+	case r.Type == "*" && r.Subtype == "*":
+		rank = 1
+	case r.Type != m.Type:
+		return 0
+	case r.Subtype == "*":
+		rank = 2
+	case r.Subtype == m.Subtype:
+		rank = 4
+	case m.suffix() == r.Subtype:
+		rank = 3
+	default:
+		return 0
+	}
+
+	for k, v := range r.Params {
+		mv, ok := m.Params[k]
+		if !ok {
+			return 0
+		}
+		// Charset values are case insensitive, other parameter values are not
+		if mv != v && !(k == "charset" && strings.EqualFold(mv, v)) {
+			return 0
+		}
+	}
+
+	return rank*100 + len(r.Params)
+}
+
+// matchMediaType returns the declared media type or range most specifically matching the content type
+func matchMediaType(declared []string, contentType string) (string, bool) {
+	m, ok := parseMediaRange(contentType)
+	if !ok {
+		return "", false
+	}
+
+	// Sort for a stable choice between equally specific declarations
+	sorted := append([]string{}, declared...)
+	sort.Strings(sorted)
+
+	best, bestScore := "", 0
+	for _, d := range sorted {
+		r, ok := parseMediaRange(d)
+		if !ok {
+			continue
+		}
+		if score := r.matchScore(m); score > bestScore {
+			best, bestScore = d, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// requestContentType returns the Content-Type of the request, or an empty string if not present.
+// Several Content-Type headers are accepted only if they are equivalent.
+func requestContentType(req *http.Request) (string, error) {
+	values := req.Header.Values(echo.HeaderContentType)
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	first := ""
+	for i, v := range values {
+		mt, params, err := mime.ParseMediaType(v)
+		if err != nil {
+			return "", ErrContentTypeNotSupported
+		}
+		if norm := mime.FormatMediaType(mt, params); i == 0 {
+			first = norm
+		} else if norm != first {
+			return "", ErrContentTypeNotSupported
+		}
+	}
+	return values[0], nil
+}
+
+// hasRequestBody returns whether the request has a body, as for a Content-Length other than zero or chunked transfer encoding
+func hasRequestBody(req *http.Request) bool {
+	return req.ContentLength != 0 || len(req.TransferEncoding) > 0
+}
@@ -0,0 +1,245 @@
+package echopen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartMaxMemory is the memory used when parsing multipart forms, matching echo, with larger files stored on disk
+const multipartMaxMemory = 32 << 20
+
+type MultipartBodyConfig struct {
+	Description string
+	Target      interface{}
+	// Maximum size in bytes of each file, or zero for no limit
+	MaxFileSize int64
+	// Maximum size in bytes of the request body, or zero for no limit
+	MaxTotalSize int64
+	Required     bool
+}
+
+// WithMultipartBodyConfig extracts type information from the target struct to populate a multipart/form-data requestBody.
+// Parts are named by the form struct tag, and the encoding of each part is populated from the contentType and headers struct tags.
+// A bound struct of the same type is added to the context under the key "body" during each request.
+func WithMultipartBodyConfig(config *MultipartBodyConfig) RouteConfigFunc {
+	t := reflect.TypeOf(config.Target)
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("echopen: struct expected, received %s", t.Kind()))
+	}
+
+	return func(rw *RouteWrapper) *RouteWrapper {
+		s := rw.API.structFieldsSchema(t, "form")
+		rw.RequestBodySchema[echo.MIMEMultipartForm] = s
+		rw.MultipartConfig = config
+
+		rw.Operation.AddRequestBody(&v320.RequestBody{
+			Description: config.Description,
+			Required:    config.Required,
+			Content: map[string]*v320.MediaTypeObject{
+				echo.MIMEMultipartForm: {
+					Schema:   v320.NewSchemaValue(s),
+					Encoding: multipartEncoding(t),
+				},
+			},
+		})
+
+		return rw
+	}
+}
+
+// WithMultipartBodyStruct is the simplified form of WithMultipartBodyConfig, without size limits
+func WithMultipartBodyStruct(description string, target interface{}) RouteConfigFunc {
+	return WithMultipartBodyConfig(&MultipartBodyConfig{
+		Description: description,
+		Target:      target,
+	})
+}
+
+// multipartEncoding returns the encoding of each part from the contentType and headers struct tags.
+// Both tags accept a comma separated list, content types may use wildcards such as image/*.
+func multipartEncoding(t reflect.Type) map[string]*v320.Encoding {
+	encoding := map[string]*v320.Encoding{}
+	walkStructFields(t, "form", nil, func(f reflect.StructField, name string, _ []int) {
+		ct := getEchoTag(f, "contentType")
+		headers := getEchoTag(f, "headers")
+		if ct == "" && headers == "" {
+			return
+		}
+
+		enc := &v320.Encoding{}
+		if ct != "" {
+			types := []string{}
+			for _, typ := range strings.Split(ct, ",") {
+				types = append(types, strings.TrimSpace(typ))
+			}
+			enc.ContentType = strings.Join(types, ", ")
+		}
+		if headers != "" {
+			enc.Headers = map[string]*v320.Ref[v320.Header]{}
+			for _, h := range strings.Split(headers, ",") {
+				enc.Headers[http.CanonicalHeaderKey(strings.TrimSpace(h))] = &v320.Ref[v320.Header]{
+					Value: &v320.Header{Schema: &v320.Schema{Type: v320.StringSchemaType}},
+				}
+			}
+		}
+		encoding[name] = enc
+	})
+
+	if len(encoding) == 0 {
+		return nil
+	}
+	return encoding
+}
+
+// checkMultipart parses a multipart/form-data request body, enforcing the size limits of the route and the content types of file parts
+func (r *RouteWrapper) checkMultipart(c echo.Context) error {
+	req := c.Request()
+	if !hasRequestBody(req) {
+		return nil
+	}
+	if mt, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType)); mt != echo.MIMEMultipartForm {
+		return nil
+	}
+	content := r.requestBodyContent(echo.MIMEMultipartForm)
+	if content == nil {
+		return nil
+	}
+
+	cfg := r.MultipartConfig
+	if cfg == nil {
+		cfg = &MultipartBodyConfig{}
+	}
+
+	if cfg.MaxTotalSize > 0 {
+		if req.ContentLength > cfg.MaxTotalSize {
+			return ErrRequestTooLarge
+		}
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, cfg.MaxTotalSize)
+	}
+
+	// The parsed form is kept by the request for binding
+	form, err := readMultipartForm(req, cfg.MaxFileSize)
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.Is(err, ErrRequestTooLarge) {
+			return err
+		} else if errors.As(err, &mbe) {
+			return fmt.Errorf("%w: %w", ErrRequestTooLarge, err)
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	for name, files := range form.File {
+		enc := content.Encoding[name]
+		for _, fh := range files {
+			if enc != nil && enc.ContentType != "" && !mediaTypeAllowed(enc.ContentType, fh.Header.Get(echo.HeaderContentType)) {
+				return fmt.Errorf("%w: part '%s'", ErrContentTypeNotSupported, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// readMultipartForm parses a multipart/form-data request body into the request MultipartForm and Form fields.
+// Parts are streamed through a limit on the size of each file before being re-encoded for multipart.Reader.ReadForm,
+// so oversized files are rejected as soon as the limit is passed rather than after the whole body is read.
+func readMultipartForm(req *http.Request, maxFileSize int64) (*multipart.Form, error) {
+	mr, err := req.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	done := make(chan error, 1)
+	go func() {
+		err := copyMultipartParts(mr, w, maxFileSize)
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	form, err := multipart.NewReader(pr, w.Boundary()).ReadForm(multipartMaxMemory)
+	pr.Close()
+	if copyErr := <-done; copyErr != nil && !errors.Is(copyErr, io.ErrClosedPipe) {
+		// Errors reading the request take precedence over the truncated form they cause
+		if form != nil {
+			_ = form.RemoveAll()
+		}
+		return nil, copyErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Match the request fields populated by http.Request.ParseMultipartForm
+	req.MultipartForm = form
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	for k, v := range form.Value {
+		req.Form[k] = append(req.Form[k], v...)
+		req.PostForm[k] = append(req.PostForm[k], v...)
+	}
+	return form, nil
+}
+
+// copyMultipartParts copies each part to the writer, failing with ErrRequestTooLarge if a file part exceeds the maximum size
+func copyMultipartParts(mr *multipart.Reader, w *multipart.Writer, maxFileSize int64) error {
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		dst, err := w.CreatePart(p.Header)
+		if err != nil {
+			return err
+		}
+
+		var src io.Reader = p
+		limited := maxFileSize > 0 && p.FileName() != ""
+		if limited {
+			src = io.LimitReader(p, maxFileSize+1)
+		}
+		n, err := io.Copy(dst, src)
+		if err != nil {
+			return err
+		}
+		if limited && n > maxFileSize {
+			return fmt.Errorf("%w: part '%s'", ErrRequestTooLarge, p.FormName())
+		}
+	}
+}
+
+// requestBodyContent returns the declared request body content for the media type, or nil if not declared
+func (r *RouteWrapper) requestBodyContent(mime string) *v320.MediaTypeObject {
+	if r.Operation.RequestBody == nil {
+		return nil
+	}
+	rb, _ := r.Operation.RequestBody.DeRef(r.API.Spec.Components).(*v320.RequestBody)
+	if rb == nil {
+		return nil
+	}
+	return rb.Content[mime]
+}
+
+// mediaTypeAllowed checks a content type against a comma separated list of media types, which may use ranges such as image/*
+func mediaTypeAllowed(allowed string, contentType string) bool {
+	_, ok := matchMediaType(strings.Split(allowed, ","), contentType)
+	return ok
+}
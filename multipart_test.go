@@ -0,0 +1,151 @@
+package echopen_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type multipartFile struct {
+	Field       string
+	Name        string
+	ContentType string
+	Data        string
+}
+
+func multipartBody(fields map[string]string, files ...multipartFile) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for k, v := range fields {
+		_ = w.WriteField(k, v)
+	}
+	for _, f := range files {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="`+f.Field+`"; filename="`+f.Name+`"`)
+		h.Set("Content-Type", f.ContentType)
+		pw, _ := w.CreatePart(h)
+		_, _ = pw.Write([]byte(f.Data))
+	}
+	_ = w.Close()
+	return body, w.FormDataContentType()
+}
+
+func TestMultipartBody(t *testing.T) {
+	type Upload struct {
+		Title  string                  `form:"title" validate:"required"`
+		Avatar *multipart.FileHeader   `form:"avatar" contentType:"image/png, image/jpeg" headers:"x-checksum"`
+		Docs   []*multipart.FileHeader `form:"docs" contentType:"text/*"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST(
+		"/",
+		func(c echo.Context) error {
+			body, err := echopen.Body[*Upload](c)
+			assert.NoError(t, err)
+			assert.Equal(t, "doc", body.Title)
+			assert.Equal(t, "a.png", body.Avatar.Filename)
+			assert.Len(t, body.Docs, 2)
+			return c.NoContent(204)
+		},
+		echopen.WithMultipartBodyConfig(&echopen.MultipartBodyConfig{
+			Description:  "Upload",
+			Target:       Upload{},
+			MaxFileSize:  10,
+			MaxTotalSize: 1024,
+		}),
+	)
+
+	rb := api.Spec.Paths["/"].Value.Post.RequestBody.Value
+	assert.Equal(t, "Upload", rb.Description)
+	mt := rb.Content[echo.MIMEMultipartForm]
+	if assert.NotNil(t, mt) {
+		assert.Equal(t, "image/png, image/jpeg", mt.Encoding["avatar"].ContentType)
+		assert.Contains(t, mt.Encoding["avatar"].Headers, "X-Checksum")
+		assert.Equal(t, "text/*", mt.Encoding["docs"].ContentType)
+		assert.NotContains(t, mt.Encoding, "title")
+		assert.Equal(t, "binary", string(mt.Schema.Value.Properties["docs"].Value.Items.Value.Format))
+	}
+
+	png := multipartFile{"avatar", "a.png", "image/png", "png"}
+	docs := []multipartFile{{"docs", "a.txt", "text/plain", "a"}, {"docs", "b.csv", "text/csv", "b"}}
+
+	type tcd struct {
+		Name     string
+		Files    []multipartFile
+		Chunked  bool
+		Expected int
+	}
+
+	defs := []tcd{
+		{"valid", append([]multipartFile{png}, docs...), false, 204},
+		{"part_content_type", []multipartFile{{"avatar", "a.gif", "image/gif", "gif"}}, false, 415},
+		{"part_wildcard_content_type", []multipartFile{png, {"docs", "a.pdf", "application/pdf", "pdf"}}, false, 415},
+		{"file_size", []multipartFile{{"avatar", "a.png", "image/png", strings.Repeat("x", 11)}}, false, 413},
+		{"total_size", []multipartFile{{"docs", "a.txt", "text/plain", strings.Repeat("x", 2048)}}, false, 413},
+		{"total_size_chunked", []multipartFile{{"docs", "a.txt", "text/plain", strings.Repeat("x", 2048)}}, true, 413},
+	}
+
+	for _, d := range defs {
+		t.Run(d.Name, func(t *testing.T) {
+			body, ct := multipartBody(map[string]string{"title": "doc"}, d.Files...)
+			var r io.Reader = body
+			if d.Chunked {
+				// Hide the length of the body
+				r = io.MultiReader(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", r)
+			req.Header.Set(echo.HeaderContentType, ct)
+			if d.Chunked {
+				req.ContentLength = -1
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, d.Expected, res.Result().StatusCode)
+		})
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestMultipartFileSizeStreaming(t *testing.T) {
+	type Upload struct {
+		Avatar *multipart.FileHeader `form:"avatar"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST("/", func(c echo.Context) error {
+		return c.NoContent(204)
+	}, echopen.WithMultipartBodyConfig(&echopen.MultipartBodyConfig{
+		Target:      Upload{},
+		MaxFileSize: 10,
+	}))
+
+	// The oversized file is rejected without reading the rest of the body
+	body, ct := multipartBody(nil, multipartFile{"avatar", "a.png", "image/png", strings.Repeat("x", 64<<20)})
+	r := &countingReader{r: body}
+	req := httptest.NewRequest(http.MethodPost, "/", r)
+	req.Header.Set(echo.HeaderContentType, ct)
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 413, res.Result().StatusCode)
+	assert.Less(t, r.n, 1<<20)
+}
@@ -1,5 +1,12 @@
 package v320
 
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
 type TODO interface{}
 
 // 4.8.2 https://spec.openapis.org/oas/v3.2.0#info-object
@@ -78,7 +85,7 @@ type Parameter struct {
 	AllowEmptyValue bool              `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
 
 	Style         string     `json:"style,omitempty" yaml:"style,omitempty"`
-	Explode       bool       `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Explode       *bool      `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool       `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
 	Schema        *Schema    `json:"schema,omitempty" yaml:"schema,omitempty"`
 	Examples      []*Example `json:"examples,omitempty" yaml:"examples,omitempty"`
@@ -115,10 +122,10 @@ type MediaTypeObject struct {
 
 // 4.8.15 https://spec.openapis.org/oas/v3.2.0#encoding-object
 type Encoding struct {
-	ContentType   string                  `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	ContentType   string                  `json:"contentType,omitempty" yaml:"contentType,omitempty"`
 	Headers       map[string]*Ref[Header] `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Style         string                  `json:"style,omitempty" yaml:"style,omitempty"`
-	Explode       bool                    `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Explode       *bool                   `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool                    `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
 }
 
@@ -146,7 +153,7 @@ type Header struct {
 	AllowEmptyValue bool   `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
 
 	Style         string     `json:"style,omitempty" yaml:"style,omitempty"`
-	Explode       bool       `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Explode       *bool      `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool       `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
 	Schema        *Schema    `json:"schema,omitempty" yaml:"schema,omitempty"`
 	Examples      []*Example `json:"examples,omitempty" yaml:"examples,omitempty"`
@@ -185,6 +192,64 @@ type SecurityScheme struct {
 	BearerFormat     string             `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
 	Flows            *OAuthFlows        `json:"flows,omitempty" yaml:"flows,omitempty"`
 	OpenIDConnectURL string             `json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
+
+	// Specification extensions, keys must begin with "x-"
+	Extensions map[string]interface{} `json:"-" yaml:",inline"`
+}
+
+func (s *SecurityScheme) MarshalJSON() ([]byte, error) {
+	type securityScheme SecurityScheme
+	data, err := json.Marshal((*securityScheme)(s))
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+
+	// Append extensions to the object in a stable order
+	keys := make([]string, 0, len(s.Extensions))
+	for k := range s.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for i, k := range keys {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		value, err := json.Marshal(s.Extensions[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	type securityScheme SecurityScheme
+	if err := json.Unmarshal(data, (*securityScheme)(s)); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for k, v := range fields {
+		if strings.HasPrefix(k, "x-") {
+			if s.Extensions == nil {
+				s.Extensions = map[string]interface{}{}
+			}
+			s.Extensions[k] = v
+		}
+	}
+
+	return nil
 }
 
 type SecuritySchemeType string
@@ -215,3 +280,11 @@ type OAuthFlow struct {
 
 // 4.8.30 https://spec.openapis.org/oas/v3.2.0#security-requirement-object
 type SecurityRequirement map[string][]string
+
+// SecurityRequirements is a list of alternative security requirements.
+// A nil list is omitted, whereas an empty list is serialized to explicitly remove inherited requirements.
+type SecurityRequirements []*SecurityRequirement
+
+func (s SecurityRequirements) IsZero() bool {
+	return s == nil
+}
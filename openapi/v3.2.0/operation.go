@@ -12,7 +12,7 @@ type Operation struct {
 	Responses    map[string]*Ref[Response] `json:"responses,omitempty" yaml:"responses,omitempty"`
 	Callbacks    map[string]*Ref[Callback] `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
 	Deprecated   bool                      `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
-	Security     []*SecurityRequirement    `json:"security,omitempty" yaml:"security,omitempty"`
+	Security     SecurityRequirements      `json:"security,omitzero" yaml:"security,omitempty"`
 	Servers      []*Server                 `json:"servers,omitempty" yaml:"servers,omitempty"`
 }
 
@@ -2,10 +2,6 @@ package v320
 
 import (
 	"reflect"
-	"strconv"
-	"time"
-
-	"github.com/gofrs/uuid"
 )
 
 // 4.8.24 https://spec.openapis.org/oas/v3.2.0#schema-object
@@ -76,7 +72,23 @@ const (
 	DateSchemaFormat     SchemaFormat = "date"
 	TimeSchemaFormat     SchemaFormat = "time"
 	DurationSchemaFormat SchemaFormat = "duration"
-	// TODO
+	ByteSchemaFormat     SchemaFormat = "byte"
+	BinarySchemaFormat   SchemaFormat = "binary"
+	EmailSchemaFormat    SchemaFormat = "email"
+	URISchemaFormat      SchemaFormat = "uri"
+	UUIDSchemaFormat     SchemaFormat = "uuid"
+	IPv4SchemaFormat     SchemaFormat = "ipv4"
+	IPv6SchemaFormat     SchemaFormat = "ipv6"
+	Int8SchemaFormat     SchemaFormat = "int8"
+	Int16SchemaFormat    SchemaFormat = "int16"
+	Int32SchemaFormat    SchemaFormat = "int32"
+	Int64SchemaFormat    SchemaFormat = "int64"
+	CharSchemaFormat     SchemaFormat = "char"
+	Uint16SchemaFormat   SchemaFormat = "uint16"
+	Uint32SchemaFormat   SchemaFormat = "uint32"
+	Uint64SchemaFormat   SchemaFormat = "uint64"
+	FloatSchemaFormat    SchemaFormat = "float"
+	DoubleSchemaFormat   SchemaFormat = "double"
 )
 
 func NewSchemaValue(s *Schema) *Ref[Schema] {
@@ -86,108 +98,3 @@ func NewSchemaValue(s *Schema) *Ref[Schema] {
 func NewSchemaRef(s string) *Ref[Schema] {
 	return &Ref[Schema]{Ref: s}
 }
-
-func (s *Schema) FromString(val string) interface{} {
-	if s == nil {
-		return val
-	}
-
-	switch s.Type {
-	case "string":
-		switch s.Format {
-		case "date-time":
-			t, err := time.Parse(time.RFC3339, val)
-			if err != nil {
-				return nil
-			}
-			return t
-		case "uuid":
-			t, err := uuid.FromString(val)
-			if err != nil {
-				return nil
-			}
-			return t
-		default:
-			return val
-		}
-	case "integer":
-		switch s.Format {
-		case "uint64":
-			i, err := strconv.ParseUint(val, 10, 64)
-			if err != nil {
-				return nil
-			}
-			return uint64(i)
-		case "uint32":
-			i, err := strconv.ParseUint(val, 10, 32)
-			if err != nil {
-				return nil
-			}
-			return uint32(i)
-		case "uint16":
-			i, err := strconv.ParseUint(val, 10, 16)
-			if err != nil {
-				return nil
-			}
-			return uint16(i)
-		case "char":
-			i, err := strconv.ParseUint(val, 10, 8)
-			if err != nil {
-				return nil
-			}
-			return uint8(i)
-		case "int64":
-			i, err := strconv.ParseInt(val, 10, 64)
-			if err != nil {
-				return nil
-			}
-			return int64(i)
-		case "int32":
-			i, err := strconv.ParseInt(val, 10, 32)
-			if err != nil {
-				return nil
-			}
-			return int32(i)
-		case "int16":
-			i, err := strconv.ParseInt(val, 10, 16)
-			if err != nil {
-				return nil
-			}
-			return int16(i)
-		case "int8":
-			i, err := strconv.ParseInt(val, 10, 8)
-			if err != nil {
-				return nil
-			}
-			return int8(i)
-		default:
-			i, err := strconv.ParseInt(val, 10, 64)
-			if err != nil {
-				return nil
-			}
-			return int(i)
-		}
-	case "number":
-		switch s.Format {
-		case "float":
-			f, err := strconv.ParseFloat(val, 32)
-			if err != nil {
-				return nil
-			}
-			return float32(f)
-		default:
-			f, err := strconv.ParseFloat(val, 64)
-			if err != nil {
-				return nil
-			}
-			return f
-		}
-	case "bool":
-		b, err := strconv.ParseBool(val)
-		if err != nil {
-			return nil
-		}
-		return b
-	}
-	return nil
-}
@@ -0,0 +1,208 @@
+package v320
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ISO 8601 durations, such as P3DT4H30M or PT0.5S
+var durationPattern = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// FromString converts a string to a value of the schema type and format.
+// Arrays are split on commas and each item converted by the items schema, returning []interface{}.
+// Values with no schema or type are returned unchanged.
+func (s *Schema) FromString(val string) (interface{}, error) {
+	if s == nil {
+		return val, nil
+	}
+
+	v, err := s.convertString(val)
+	if err != nil {
+		if s.Format != "" {
+			return nil, fmt.Errorf("invalid %s %s value '%s': %w", s.Type, s.Format, val, err)
+		}
+		return nil, fmt.Errorf("invalid %s value '%s': %w", s.Type, val, err)
+	}
+	return v, nil
+}
+
+func (s *Schema) convertString(val string) (interface{}, error) {
+	switch s.Type {
+	case "":
+		return val, nil
+
+	case StringSchemaType:
+		return convertStringFormat(s.Format, val)
+
+	case IntegerSchemaType:
+		switch s.Format {
+		case Uint64SchemaFormat:
+			return strconv.ParseUint(val, 10, 64)
+		case Uint32SchemaFormat:
+			i, err := strconv.ParseUint(val, 10, 32)
+			return uint32(i), err
+		case Uint16SchemaFormat:
+			i, err := strconv.ParseUint(val, 10, 16)
+			return uint16(i), err
+		case CharSchemaFormat:
+			i, err := strconv.ParseUint(val, 10, 8)
+			return uint8(i), err
+		case Int64SchemaFormat:
+			return strconv.ParseInt(val, 10, 64)
+		case Int32SchemaFormat:
+			i, err := strconv.ParseInt(val, 10, 32)
+			return int32(i), err
+		case Int16SchemaFormat:
+			i, err := strconv.ParseInt(val, 10, 16)
+			return int16(i), err
+		case Int8SchemaFormat:
+			i, err := strconv.ParseInt(val, 10, 8)
+			return int8(i), err
+		default:
+			return strconv.Atoi(val)
+		}
+
+	case NumberSchemaType:
+		if s.Format == FloatSchemaFormat {
+			f, err := strconv.ParseFloat(val, 32)
+			return float32(f), err
+		}
+		return strconv.ParseFloat(val, 64)
+
+	case BooleanSchemaType:
+		return strconv.ParseBool(val)
+
+	case ArraySchemaType:
+		var items *Schema
+		if s.Items != nil {
+			items = s.Items.Value
+		}
+		vals := []interface{}{}
+		if val == "" {
+			return vals, nil
+		}
+		for _, part := range strings.Split(val, ",") {
+			v, err := items.FromString(part)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return vals, nil
+
+	case NullSchemaType:
+		if val != "" && val != "null" {
+			return nil, errors.New("expected null")
+		}
+		return nil, nil
+	}
+
+	return nil, errors.New("type cannot be converted from a string")
+}
+
+func convertStringFormat(format SchemaFormat, val string) (interface{}, error) {
+	switch format {
+	case DateTimeSchemaFormat:
+		return time.Parse(time.RFC3339, val)
+
+	case DateSchemaFormat:
+		return time.Parse(time.DateOnly, val)
+
+	case TimeSchemaFormat:
+		// RFC 3339 full-time, with the offset optional
+		if t, err := time.Parse("15:04:05Z07:00", val); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.TimeOnly, val)
+
+	case DurationSchemaFormat:
+		return parseDuration(val)
+
+	case ByteSchemaFormat:
+		if b, err := base64.StdEncoding.DecodeString(val); err == nil {
+			return b, nil
+		}
+		return base64.URLEncoding.DecodeString(val)
+
+	case EmailSchemaFormat:
+		addr, err := mail.ParseAddress(val)
+		if err != nil {
+			return nil, err
+		}
+		if addr.Address != val {
+			return nil, errors.New("display names are not allowed")
+		}
+		return val, nil
+
+	case URISchemaFormat:
+		u, err := url.Parse(val)
+		if err != nil {
+			return nil, err
+		}
+		if !u.IsAbs() {
+			return nil, errors.New("URI is not absolute")
+		}
+		return val, nil
+
+	case IPv4SchemaFormat:
+		addr, err := netip.ParseAddr(val)
+		if err != nil {
+			return nil, err
+		}
+		if !addr.Is4() {
+			return nil, errors.New("not an IPv4 address")
+		}
+		return val, nil
+
+	case IPv6SchemaFormat:
+		addr, err := netip.ParseAddr(val)
+		if err != nil {
+			return nil, err
+		}
+		if !addr.Is6() {
+			return nil, errors.New("not an IPv6 address")
+		}
+		return val, nil
+
+	case UUIDSchemaFormat:
+		return uuid.FromString(val)
+	}
+
+	return val, nil
+}
+
+// parseDuration parses an ISO 8601 duration, falling back to the Go duration format.
+// Years and months have no fixed length and are not supported.
+func parseDuration(val string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(val)
+	if m == nil || val == "P" || strings.HasSuffix(val, "T") {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d, nil
+		}
+		return 0, errors.New("not an ISO 8601 duration")
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(f * float64(unit))
+	}
+	return d, nil
+}
@@ -1,6 +1,7 @@
 package echopen
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
 
@@ -20,7 +21,7 @@ type HeaderParameterConfig struct {
 	Required      bool
 	Examples      []*v320.Example
 	Style         string
-	Explode       bool
+	Explode       *bool
 	Schema        *v320.Schema
 	AllowMultiple bool
 }
@@ -38,17 +39,29 @@ type QueryParameterConfig struct {
 	Required    bool
 	Examples    []*v320.Example
 	Style       string
-	Explode     bool
+	Explode     *bool
 	Schema      *v320.Schema
 }
 
 func WithParameter(param *v320.Parameter) RouteConfigFunc {
+	checkParameterStyle(param)
+	checkParameterPattern(param)
+
 	return func(rw *RouteWrapper) *RouteWrapper {
 		rw.Operation.AddParameter(param)
 		return rw
 	}
 }
 
+// checkParameterPattern panics if the pattern of the parameter schema is not a valid regular expression
+func checkParameterPattern(param *v320.Parameter) {
+	if param.Schema != nil && param.Schema.Pattern != "" {
+		if _, err := compileSchemaPattern(param.Schema.Pattern); err != nil {
+			panic(fmt.Sprintf("echopen: invalid pattern for parameter '%s': %s", param.Name, err))
+		}
+	}
+}
+
 func WithPathParameterConfig(c *PathParameterConfig) RouteConfigFunc {
 	return WithParameter(&v320.Parameter{
 		Name:        c.Name,
@@ -60,14 +73,24 @@ func WithPathParameterConfig(c *PathParameterConfig) RouteConfigFunc {
 	})
 }
 
+// WithHeaderParameterConfig adds a header parameter to the operation.
+// If AllowMultiple is set, a non-array schema is wrapped in an array to collect the values of every header line.
 func WithHeaderParameterConfig(c *HeaderParameterConfig) RouteConfigFunc {
+	schema := c.Schema
+	if c.AllowMultiple && (schema == nil || schema.Type != v320.ArraySchemaType) {
+		schema = &v320.Schema{Type: v320.ArraySchemaType}
+		if c.Schema != nil {
+			schema.Items = &v320.Ref[v320.Schema]{Value: c.Schema}
+		}
+	}
+
 	return WithParameter(&v320.Parameter{
 		Name:        http.CanonicalHeaderKey(c.Name),
 		In:          "header",
 		Description: c.Description,
 		Required:    c.Required,
 		Examples:    c.Examples,
-		Schema:      c.Schema,
+		Schema:      schema,
 		Explode:     c.Explode,
 		Style:       c.Style,
 	})
@@ -0,0 +1,100 @@
+package echopen
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Decoders for content-encoded parameter values by media type
+var parameterContentDecoders = map[string]func(data []byte, v interface{}) error{
+	echo.MIMEApplicationJSON: json.Unmarshal,
+	echo.MIMEApplicationXML:  xml.Unmarshal,
+	echo.MIMETextXML:         xml.Unmarshal,
+}
+
+// WithQueryParameterContent adds a query parameter whose value is a document of the given media type.
+// The schema is extracted from the target type, and a decoded value of the same type is added to the context under the key "query.<name>"
+func WithQueryParameterContent(name string, mime string, target interface{}) RouteConfigFunc {
+	return withParameterContent(v320.QueryParameter, name, mime, target)
+}
+
+// WithHeaderParameterContent adds a header parameter whose value is a document of the given media type.
+// The schema is extracted from the target type, and a decoded value of the same type is added to the context under the key "header.<name>"
+func WithHeaderParameterContent(name string, mime string, target interface{}) RouteConfigFunc {
+	return withParameterContent(v320.HeaderParameter, http.CanonicalHeaderKey(name), mime, target)
+}
+
+func withParameterContent(in v320.ParameterLocation, name string, mime string, target interface{}) RouteConfigFunc {
+	if _, ok := parameterContentDecoders[mime]; !ok {
+		panic(fmt.Sprintf("echopen: content type %s not supported for parameters", mime))
+	}
+
+	return func(rw *RouteWrapper) *RouteWrapper {
+		return WithParameter(&v320.Parameter{
+			Name: name,
+			In:   in,
+			Content: map[string]*v320.MediaTypeObject{
+				mime: {Schema: rw.API.ToSchemaRef(target)},
+			},
+		})(rw)
+	}
+}
+
+// bindContentParameter decodes a parameter serialized as a document of the media type declared by its content.
+// Returns false as the second value if the parameter is not present.
+func (r *RouteWrapper) bindContentParameter(c echo.Context, val *validator.Validate, param *v320.Parameter) (interface{}, bool, error) {
+	var raw string
+	switch param.In {
+	case v320.PathParameter:
+		raw = c.Param(param.Name)
+	case v320.QueryParameter:
+		raw = c.QueryParam(param.Name)
+	case v320.HeaderParameter:
+		raw = c.Request().Header.Get(param.Name)
+	case v320.CookieParameter:
+		if cookie, err := c.Cookie(param.Name); err == nil {
+			raw = cookie.Value
+		}
+	}
+	if raw == "" {
+		return nil, false, nil
+	}
+
+	// Content has exactly one entry
+	for mime, content := range param.Content {
+		decode, ok := parameterContentDecoders[mime]
+		if !ok || content.Schema == nil {
+			return nil, true, fmt.Errorf("%w: %s parameter '%s': content type %s not supported", ErrParameterInvalid, param.In, param.Name, mime)
+		}
+
+		typ := reflect.TypeOf((*interface{})(nil)).Elem()
+		schema, _ := content.Schema.DeRef(r.API.Spec.Components).(*v320.Schema)
+		if schema != nil && schema.SourceType != nil {
+			typ = schema.SourceType
+		}
+
+		v := reflect.New(typ)
+		if err := decode([]byte(raw), v.Interface()); err != nil {
+			return nil, true, fmt.Errorf("%w: %s parameter '%s': %w", ErrParameterInvalid, param.In, param.Name, err)
+		}
+
+		// Structs are validated and bound as a pointer, as for request bodies
+		if typ.Kind() == reflect.Struct {
+			if err := val.StructCtx(c.Request().Context(), v.Interface()); err != nil {
+				return nil, true, fmt.Errorf("%w: %s parameter '%s': %w", ErrParameterInvalid, param.In, param.Name, err)
+			}
+			return v.Interface(), true, nil
+		}
+
+		return v.Elem().Interface(), true, nil
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,68 @@
+package echopen_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterContent(t *testing.T) {
+	type Filter struct {
+		Status string `json:"status" validate:"oneof=open closed"`
+		Limit  int    `json:"limit,omitempty"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			filter, err := echopen.QueryParam[*Filter](c, "filter")
+			assert.NoError(t, err)
+			assert.Equal(t, "open", filter.Status)
+			assert.Equal(t, 5, filter.Limit)
+
+			tags, err := echopen.Header[[]string](c, "X-Tags")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, tags)
+			return c.NoContent(http.StatusNoContent)
+		},
+		echopen.WithQueryParameterContent("filter", echo.MIMEApplicationJSON, Filter{}),
+		echopen.WithHeaderParameterContent("x-tags", echo.MIMEApplicationJSON, []string{}),
+	)
+
+	type tcd struct {
+		Name     string
+		Filter   string
+		Expected int
+	}
+
+	defs := []tcd{
+		{"valid", `{"status":"open","limit":5}`, http.StatusNoContent},
+		{"malformed", `{"status":`, http.StatusBadRequest},
+		{"invalid", `{"status":"unknown"}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?filter="+url.QueryEscape(tc.Filter), nil)
+			req.Header.Set("X-Tags", `["a","b"]`)
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+
+	buf, err := json.Marshal(api.Spec)
+	assert.NoError(t, err)
+	assert.Contains(t, string(buf), `{"name":"filter","in":"query","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Filter"}}}}`)
+
+	assert.Panics(t, func() {
+		echopen.WithQueryParameterContent("filter", "application/unknown", Filter{})
+	})
+}
@@ -0,0 +1,324 @@
+package echopen
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Struct tags naming the parameter bound to each field, by parameter location
+var parameterStructTags = map[v320.ParameterLocation]string{
+	v320.QueryParameter:  "query",
+	v320.PathParameter:   "param",
+	v320.HeaderParameter: "header",
+	v320.CookieParameter: "cookie",
+}
+
+// WithPathStruct extracts type information from a provided struct to populate the OpenAPI operation path parameters.
+// Fields are named by the param struct tag.
+// A bound struct of the same type is added to the context under the key "path" during each request
+func WithPathStruct(target interface{}) RouteConfigFunc {
+	return withParameterStruct(v320.PathParameter, target)
+}
+
+// WithHeaderStruct extracts type information from a provided struct to populate the OpenAPI operation header parameters.
+// Fields are named by the header struct tag, converted to the canonical header key.
+// A bound struct of the same type is added to the context under the key "header" during each request
+func WithHeaderStruct(target interface{}) RouteConfigFunc {
+	return withParameterStruct(v320.HeaderParameter, target)
+}
+
+// WithCookieStruct extracts type information from a provided struct to populate the OpenAPI operation cookie parameters.
+// Fields are named by the cookie struct tag.
+// A bound struct of the same type is added to the context under the key "cookie" during each request
+func WithCookieStruct(target interface{}) RouteConfigFunc {
+	return withParameterStruct(v320.CookieParameter, target)
+}
+
+func withParameterStruct(in v320.ParameterLocation, target interface{}) RouteConfigFunc {
+	t := reflect.TypeOf(target)
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("echopen: struct expected, received %s", t.Kind()))
+	}
+
+	return func(rw *RouteWrapper) *RouteWrapper {
+		if rw.ParameterSchemas == nil {
+			rw.ParameterSchemas = map[v320.ParameterLocation]*v320.Schema{}
+		}
+		rw.ParameterSchemas[in] = rw.addStructParameters(in, parameterStructTags[in], t)
+
+		return rw
+	}
+}
+
+// addStructParameters adds a parameter for each field of the struct named by the tag in field order, using the full field schema.
+// Fields of embedded structs without a tag name are flattened into the parameters of the outer struct.
+// Path parameters are always required, others are required if the field has a required validate tag.
+// Returns an object schema with a property for each parameter.
+func (rw *RouteWrapper) addStructParameters(in v320.ParameterLocation, tag string, t reflect.Type) *v320.Schema {
+	s := &v320.Schema{
+		Type:       v320.ObjectSchemaType,
+		Properties: map[string]*v320.Ref[v320.Schema]{},
+		SourceType: t,
+	}
+
+	walkStructFields(t, tag, nil, func(f reflect.StructField, name string, _ []int) {
+		ref := rw.API.StructFieldToSchemaRef(f)
+		s.Properties[name] = ref
+
+		required := in == v320.PathParameter || isRequiredField(f)
+		if required {
+			s.Required = append(s.Required, name)
+		}
+
+		schema, _ := ref.DeRef(rw.API.Spec.Components).(*v320.Schema)
+		if schema == nil {
+			return
+		}
+
+		// Description belongs to the parameter rather than its schema
+		ps := *schema
+		ps.Description = ""
+
+		if in == v320.HeaderParameter {
+			name = http.CanonicalHeaderKey(name)
+		}
+
+		param := &v320.Parameter{
+			Name:        name,
+			In:          in,
+			Required:    required,
+			Deprecated:  schema.Deprecated,
+			Description: schema.Description,
+			Schema:      &ps,
+		}
+		if in == v320.QueryParameter {
+			param.Style = "form"
+		}
+
+		checkParameterPattern(param)
+		rw.Operation.AddParameter(param)
+	})
+
+	return s
+}
+
+// walkStructFields calls fn for each exported field of the struct named by the tag, in declaration order.
+// Embedded structs and pointers to structs without a tag name are walked recursively.
+func walkStructFields(t reflect.Type, tag string, index []int, fn func(f reflect.StructField, name string, index []int)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		idx := append(append([]int{}, index...), i)
+		name := strings.Split(f.Tag.Get(tag), ",")[0]
+
+		if f.Anonymous && name == "" {
+			et := f.Type
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				walkStructFields(et, tag, idx, fn)
+			}
+			continue
+		}
+
+		if name == "" || name == "-" {
+			continue
+		}
+		fn(f, name, idx)
+	}
+}
+
+// allocEmbedded allocates nil pointers to embedded structs so their fields can be bound
+func allocEmbedded(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.Anonymous || f.PkgPath != "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			allocEmbedded(fv)
+		}
+	}
+}
+
+// isRequiredField checks for a required rule in the validate tag of a struct field
+func isRequiredField(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// bindParameterStruct populates a new struct of the schema source type from the parameter values already added to the context
+func bindParameterStruct(c echo.Context, val *validator.Validate, in v320.ParameterLocation, schema *v320.Schema) error {
+	v, err := newParameterStruct(c, val, in, parameterStructTags[in], schema.SourceType)
+	if err != nil {
+		return err
+	}
+
+	// Add to context
+	c.Set(string(in), v)
+	GetRequestData(c).Structs[in] = v
+	return nil
+}
+
+// newParameterStruct returns a pointer to a new validated struct, with fields named by the tag set from the parameter values in the context
+func newParameterStruct(c echo.Context, val *validator.Validate, in v320.ParameterLocation, tag string, t reflect.Type) (interface{}, error) {
+	v := reflect.New(t)
+	allocEmbedded(v.Elem())
+
+	var bindErr error
+	walkStructFields(t, tag, nil, func(f reflect.StructField, name string, index []int) {
+		if bindErr != nil {
+			return
+		}
+		if in == v320.HeaderParameter {
+			name = http.CanonicalHeaderKey(name)
+		}
+
+		pv := c.Get(fmt.Sprintf("%s.%s", in, name))
+		if pv == nil {
+			return
+		}
+		if err := setFieldValue(v.Elem().FieldByIndex(index), pv); err != nil {
+			bindErr = fmt.Errorf("%w: %s: %w", ErrParameterInvalid, name, err)
+		}
+	})
+	if bindErr != nil {
+		return nil, bindErr
+	}
+
+	// Validate the bound struct
+	if err := val.StructCtx(c.Request().Context(), v.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParameterInvalid, err)
+	}
+
+	return v.Interface(), nil
+}
+
+// setFieldValue assigns a converted parameter value to a struct field, allocating pointers and converting slice elements
+func setFieldValue(f reflect.Value, val interface{}) error {
+	rv := reflect.ValueOf(val)
+
+	if rv.Type().AssignableTo(f.Type()) {
+		f.Set(rv)
+		return nil
+	}
+
+	if f.Kind() == reflect.Pointer {
+		p := reflect.New(f.Type().Elem())
+		if err := setFieldValue(p.Elem(), val); err != nil {
+			return err
+		}
+		f.Set(p)
+		return nil
+	}
+
+	if vals, ok := val.([]interface{}); ok && f.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(f.Type(), len(vals), len(vals))
+		for i, item := range vals {
+			if err := setFieldValue(s.Index(i), item); err != nil {
+				return err
+			}
+		}
+		f.Set(s)
+		return nil
+	}
+
+	if str, ok := val.(string); ok {
+		if u, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(str))
+		}
+	}
+
+	if isNumberKind(rv.Kind()) && isNumberKind(f.Kind()) {
+		return setNumberValue(f, rv)
+	}
+
+	// Only convert between values of the same kind, such as string to a named string type, to avoid int to string conversions
+	if rv.Kind() == f.Kind() {
+		if rv.CanConvert(f.Type()) {
+			f.Set(rv.Convert(f.Type()))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot assign %s to %s", rv.Type(), f.Type())
+}
+
+// setNumberValue converts a number to the kind of the field, failing if the value would overflow or lose a fraction
+func setNumberValue(f reflect.Value, rv reflect.Value) error {
+	overflow := false
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case rv.CanInt():
+			overflow = f.OverflowInt(rv.Int())
+		case rv.CanUint():
+			overflow = rv.Uint() > math.MaxInt64 || f.OverflowInt(int64(rv.Uint()))
+		default:
+			x := rv.Float()
+			if x != math.Trunc(x) {
+				return fmt.Errorf("value %v is not a whole number", x)
+			}
+			overflow = x < math.MinInt64 || x >= math.MaxInt64 || f.OverflowInt(int64(x))
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch {
+		case rv.CanInt():
+			overflow = rv.Int() < 0 || f.OverflowUint(uint64(rv.Int()))
+		case rv.CanUint():
+			overflow = f.OverflowUint(rv.Uint())
+		default:
+			x := rv.Float()
+			if x != math.Trunc(x) {
+				return fmt.Errorf("value %v is not a whole number", x)
+			}
+			overflow = x < 0 || x >= math.MaxUint64 || f.OverflowUint(uint64(x))
+		}
+
+	default:
+		if rv.CanFloat() {
+			overflow = f.OverflowFloat(rv.Float())
+		}
+	}
+
+	if overflow {
+		return fmt.Errorf("value %v overflows %s", rv.Interface(), f.Type())
+	}
+	f.Set(rv.Convert(f.Type()))
+	return nil
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package echopen_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anteo/echopen/v2"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutePathStruct(t *testing.T) {
+	type PathStruct struct {
+		ID    int       `param:"id" description:"ID" validate:"max=100"`
+		Group uuid.UUID `param:"group"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/:group/:id",
+		func(c echo.Context) error {
+			path := c.Get("path").(*PathStruct)
+			assert.Equal(t, 42, path.ID)
+			assert.Equal(t, uuid.FromStringOrNil("11c7810d-6627-497a-91e9-e3dc4812ce30"), path.Group)
+			return c.NoContent(204)
+		},
+		echopen.WithPathStruct(PathStruct{}),
+	)
+
+	_, res := executeRequest(api, http.MethodGet, "/11c7810d-6627-497a-91e9-e3dc4812ce30/42", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/11c7810d-6627-497a-91e9-e3dc4812ce30/420", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+
+	params := api.Spec.Paths["/{group}/{id}"].Value.Get.Parameters
+	assert.Len(t, params, 2)
+	assert.Equal(t, "id", params[0].Value.Name)
+	assert.Equal(t, "group", params[1].Value.Name)
+	for _, p := range params {
+		assert.Equal(t, "path", string(p.Value.In))
+		assert.True(t, p.Value.Required)
+	}
+}
+
+func TestRouteHeaderStruct(t *testing.T) {
+	type HeaderStruct struct {
+		RequestID string    `header:"x-request-id" validate:"required"`
+		Time      time.Time `header:"X-Request-Time"`
+		Tags      []string  `header:"X-Tags"`
+		Limit     *int      `header:"X-Limit"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			hdr := c.Get("header").(*HeaderStruct)
+			assert.Equal(t, "abc", hdr.RequestID)
+			assert.Equal(t, 2024, hdr.Time.Year())
+			assert.Equal(t, []string{"a", "b"}, hdr.Tags)
+			assert.Equal(t, 10, *hdr.Limit)
+			return c.NoContent(204)
+		},
+		echopen.WithHeaderStruct(HeaderStruct{}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	req.Header.Set("X-Request-Time", "2024-01-02T03:04:05Z")
+	req.Header.Set("X-Tags", "a,b")
+	req.Header.Set("X-Limit", "10")
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 204, res.Code)
+
+	req.Header.Set("X-Limit", "ten")
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 400, res.Code)
+}
+
+func TestRouteCookieStruct(t *testing.T) {
+	type CookieStruct struct {
+		Session string `cookie:"session" validate:"min=8"`
+		Theme   string `cookie:"theme"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			cookies := c.Get("cookie").(*CookieStruct)
+			assert.Equal(t, "1234123412341234", cookies.Session)
+			assert.Equal(t, "dark", cookies.Theme)
+			return c.NoContent(204)
+		},
+		echopen.WithCookieStruct(CookieStruct{}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "1234123412341234"})
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 204, res.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "1234"})
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 400, res.Code)
+}
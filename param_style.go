@@ -0,0 +1,361 @@
+package echopen
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+)
+
+// Serialization styles supported for each parameter location
+var parameterStyles = map[v320.ParameterLocation][]string{
+	v320.PathParameter:   {"simple", "label", "matrix"},
+	v320.QueryParameter:  {"form", "spaceDelimited", "pipeDelimited", "deepObject"},
+	v320.HeaderParameter: {"simple"},
+	v320.CookieParameter: {"form"},
+}
+
+// checkParameterStyle panics if the parameter style is not defined for its location
+func checkParameterStyle(param *v320.Parameter) {
+	if param.Style == "" {
+		return
+	}
+	styles, ok := parameterStyles[param.In]
+	if !ok {
+		return
+	}
+	for _, s := range styles {
+		if s == param.Style {
+			return
+		}
+	}
+	panic(fmt.Sprintf("echopen: style '%s' not supported for %s parameters", param.Style, param.In))
+}
+
+// parameterStyle returns the serialization style of the parameter, defaulting by location
+func parameterStyle(param *v320.Parameter) string {
+	if param.Style != "" {
+		return param.Style
+	}
+	switch param.In {
+	case v320.QueryParameter, v320.CookieParameter:
+		return "form"
+	default:
+		return "simple"
+	}
+}
+
+// parameterExplode returns whether the parameter is exploded.
+// Explode defaults to true for form style and false for all other styles when not set.
+func parameterExplode(param *v320.Parameter) bool {
+	if param.Explode != nil {
+		return *param.Explode
+	}
+	return parameterStyle(param) == "form"
+}
+
+// schemaType returns the type of a possibly nil schema
+func schemaType(s *v320.Schema) v320.SchemaType {
+	if s == nil {
+		return ""
+	}
+	return s.Type
+}
+
+// decodePathParameter splits a path parameter value according to the simple, label, or matrix style.
+// Returns a string, []string, or map[string]string depending on the schema type.
+func decodePathParameter(param *v320.Parameter, v string) (interface{}, bool) {
+	typ := schemaType(param.Schema)
+	explode := parameterExplode(param)
+
+	switch parameterStyle(param) {
+	case "label":
+		// .blue  .blue,black  .blue.black  .R,100,G,200  .R=100.G=200
+		if !strings.HasPrefix(v, ".") {
+			return nil, false
+		}
+		sep := ","
+		if explode {
+			sep = "."
+		}
+		return decodeDelimited(v[1:], sep, typ, explode)
+
+	case "matrix":
+		// ;color=blue  ;color=blue,black  ;color=blue;color=black  ;color=R,100,G,200  ;R=100;G=200
+		if !strings.HasPrefix(v, ";") {
+			return nil, false
+		}
+		prefix := param.Name + "="
+		if explode && typ == v320.ArraySchemaType {
+			vals := []string{}
+			for _, part := range strings.Split(v[1:], ";") {
+				if !strings.HasPrefix(part, prefix) {
+					return nil, false
+				}
+				vals = append(vals, part[len(prefix):])
+			}
+			return vals, true
+		} else if explode && typ == v320.ObjectSchemaType {
+			return decodeDelimited(v[1:], ";", typ, true)
+		}
+		if !strings.HasPrefix(v[1:], prefix) {
+			return nil, false
+		}
+		return decodeDelimited(v[1+len(prefix):], ",", typ, false)
+
+	default:
+		// blue  blue,black  R,100,G,200  R=100,G=200
+		return decodeDelimited(v, ",", typ, explode)
+	}
+}
+
+// decodeHeaderParameter splits the header values according to the simple style.
+// Values of array and object parameters may be split over multiple header lines.
+func decodeHeaderParameter(param *v320.Parameter, lines []string) (interface{}, bool) {
+	typ := schemaType(param.Schema)
+	if typ != v320.ArraySchemaType && typ != v320.ObjectSchemaType {
+		return lines[0], true
+	}
+
+	// Trim optional whitespace around list elements
+	parts := []string{}
+	for _, line := range lines {
+		for _, part := range strings.Split(line, ",") {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+	return decodeDelimited(strings.Join(parts, ","), ",", typ, parameterExplode(param))
+}
+
+// decodeQueryParameter reads a query parameter according to the form, spaceDelimited, pipeDelimited, or deepObject style.
+// Returns false as the second value if the parameter is not present.
+func decodeQueryParameter(param *v320.Parameter, query url.Values) (interface{}, bool, error) {
+	typ := schemaType(param.Schema)
+	explode := parameterExplode(param)
+	style := parameterStyle(param)
+
+	if style == "deepObject" {
+		// color[R]=100&color[G]=200
+		obj := map[string]string{}
+		for key, vals := range query {
+			if strings.HasPrefix(key, param.Name+"[") && strings.HasSuffix(key, "]") && len(vals) > 0 {
+				obj[key[len(param.Name)+1:len(key)-1]] = vals[0]
+			}
+		}
+		return obj, len(obj) > 0, nil
+	}
+
+	if explode && typ == v320.ObjectSchemaType {
+		// R=100&G=200, each property is a separate parameter
+		obj := map[string]string{}
+		for name := range param.Schema.Properties {
+			if vals, ok := query[name]; ok && len(vals) > 0 {
+				obj[name] = vals[0]
+			}
+		}
+		return obj, len(obj) > 0, nil
+	}
+
+	vals, ok := query[param.Name]
+	if !ok || len(vals) == 0 {
+		return nil, false, nil
+	}
+
+	if explode && typ == v320.ArraySchemaType {
+		// color=blue&color=black
+		return vals, true, nil
+	}
+
+	sep := ","
+	switch style {
+	case "spaceDelimited":
+		sep = " "
+	case "pipeDelimited":
+		sep = "|"
+	}
+	v, ok := decodeDelimited(vals[0], sep, typ, false)
+	if !ok {
+		return nil, true, malformedParameter(param)
+	}
+	return v, true, nil
+}
+
+// decodeCookieParameter reads a cookie parameter according to the form style.
+// Exploded arrays are sent as repeated cookies, exploded objects as a cookie per property.
+// Returns false as the second value if the parameter is not present.
+func decodeCookieParameter(param *v320.Parameter, cookies []*http.Cookie) (interface{}, bool, error) {
+	typ := schemaType(param.Schema)
+	explode := parameterExplode(param)
+
+	values := map[string][]string{}
+	for _, cookie := range cookies {
+		values[cookie.Name] = append(values[cookie.Name], cookie.Value)
+	}
+
+	if explode && typ == v320.ObjectSchemaType {
+		obj := map[string]string{}
+		for name := range param.Schema.Properties {
+			if vals, ok := values[name]; ok {
+				obj[name] = vals[0]
+			}
+		}
+		return obj, len(obj) > 0, nil
+	}
+
+	vals, ok := values[param.Name]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if explode && typ == v320.ArraySchemaType {
+		return vals, true, nil
+	}
+
+	v, ok := decodeDelimited(vals[0], ",", typ, false)
+	if !ok {
+		return nil, true, malformedParameter(param)
+	}
+	return v, true, nil
+}
+
+// decodeDelimited splits a serialized array or object on the separator.
+// Objects are serialized as alternating keys and values, or as key=value pairs if pairs is true.
+func decodeDelimited(v string, sep string, typ v320.SchemaType, pairs bool) (interface{}, bool) {
+	switch typ {
+	case v320.ArraySchemaType:
+		if v == "" {
+			return []string{}, true
+		}
+		return strings.Split(v, sep), true
+
+	case v320.ObjectSchemaType:
+		obj := map[string]string{}
+		if v == "" {
+			return obj, true
+		}
+		parts := strings.Split(v, sep)
+		if pairs {
+			for _, part := range parts {
+				kv := strings.SplitN(part, "=", 2)
+				if len(kv) != 2 {
+					return nil, false
+				}
+				obj[kv[0]] = kv[1]
+			}
+			return obj, true
+		}
+		if len(parts)%2 != 0 {
+			return nil, false
+		}
+		for i := 0; i < len(parts); i += 2 {
+			obj[parts[i]] = parts[i+1]
+		}
+		return obj, true
+
+	default:
+		return v, true
+	}
+}
+
+// missingParameter handles a parameter absent from the request.
+// Returns ErrRequiredParameterMissing for required parameters, otherwise adds any schema default to the context.
+func (r *RouteWrapper) missingParameter(c echo.Context, param *v320.Parameter) error {
+	if param.Required {
+		return ErrRequiredParameterMissing
+	}
+	if param.Schema == nil || param.Schema.Default == nil {
+		return nil
+	}
+
+	val := param.Schema.Default
+	if def, ok := val.(string); ok {
+		// Defaults from struct tags are strings, convert to the schema type
+		raw, ok := decodeDelimited(def, ",", param.Schema.Type, false)
+		if !ok {
+			return nil
+		}
+		var err error
+		if val, err = r.convertParameter(param.Schema, raw); err != nil {
+			return nil
+		}
+	}
+
+	setParameter(c, param, val)
+	return nil
+}
+
+// convertParameter converts a decoded parameter to the types given by the schema.
+// Arrays are converted to []interface{} and objects to map[string]interface{}.
+func (r *RouteWrapper) convertParameter(schema *v320.Schema, raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case []string:
+		var items *v320.Schema
+		if schema != nil && schema.Items != nil {
+			items, _ = schema.Items.DeRef(r.API.Spec.Components).(*v320.Schema)
+		}
+		vals := []interface{}{}
+		for _, s := range v {
+			val, err := r.API.fromString(items, s)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, val)
+		}
+		return vals, nil
+
+	case map[string]string:
+		obj := map[string]interface{}{}
+		for k, s := range v {
+			// Properties of parameters without a schema are left as strings
+			var prop *v320.Schema
+			if schema != nil {
+				if ref, ok := schema.Properties[k]; ok {
+					prop, _ = ref.DeRef(r.API.Spec.Components).(*v320.Schema)
+				} else if schema.AdditionalProperties != nil {
+					prop, _ = schema.AdditionalProperties.DeRef(r.API.Spec.Components).(*v320.Schema)
+				}
+			}
+			val, err := r.API.fromString(prop, s)
+			if err != nil {
+				return nil, fmt.Errorf("property '%s': %w", k, err)
+			}
+			obj[k] = val
+		}
+		return obj, nil
+
+	case string:
+		return r.API.fromString(schema, v)
+	}
+
+	return nil, fmt.Errorf("unexpected value %T", raw)
+}
+
+// FormatConverter converts a parameter value to a value of a schema format
+type FormatConverter func(val string) (interface{}, error)
+
+// fromString converts a parameter value using any converter registered for the schema format, otherwise the built in conversion
+func (w *APIWrapper) fromString(s *v320.Schema, val string) (interface{}, error) {
+	if s != nil && s.Format != "" {
+		if fn, ok := w.formatConverters[s.Format]; ok {
+			v, err := fn(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value '%s': %w", s.Format, val, err)
+			}
+			return v, nil
+		}
+	}
+	return s.FromString(val)
+}
+
+// invalidParameter wraps an error converting a parameter value with ErrParameterInvalid
+func invalidParameter(param *v320.Parameter, err error) error {
+	return fmt.Errorf("%w: %s parameter '%s': %w", ErrParameterInvalid, param.In, param.Name, err)
+}
+
+// malformedParameter returns ErrParameterInvalid for a value present in the request which cannot be decoded in the parameter style
+func malformedParameter(param *v320.Parameter) error {
+	return invalidParameter(param, fmt.Errorf("value does not match style '%s'", parameterStyle(param)))
+}
@@ -0,0 +1,182 @@
+package echopen_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterStyles(t *testing.T) {
+	intArray := func() *v320.Schema {
+		return &v320.Schema{Type: v320.ArraySchemaType, Items: &v320.Ref[v320.Schema]{Value: &v320.Schema{Type: v320.IntegerSchemaType}}}
+	}
+	rgbObject := func() *v320.Schema {
+		return &v320.Schema{Type: v320.ObjectSchemaType, Properties: map[string]*v320.Ref[v320.Schema]{
+			"R": {Value: &v320.Schema{Type: v320.IntegerSchemaType}},
+			"G": {Value: &v320.Schema{Type: v320.IntegerSchemaType}},
+		}}
+	}
+	ints := []interface{}{3, 4, 5}
+	rgb := map[string]interface{}{"R": 100, "G": 200}
+
+	type tcd struct {
+		Name     string
+		Param    *v320.Parameter
+		Target   string
+		Request  func(req *http.Request)
+		Expected interface{}
+	}
+
+	defs := []tcd{
+		{"path_simple_array", &v320.Parameter{In: "path", Schema: intArray()}, "/3,4,5", nil, ints},
+		{"path_simple_object", &v320.Parameter{In: "path", Schema: rgbObject()}, "/R,100,G,200", nil, rgb},
+		{"path_simple_object_explode", &v320.Parameter{In: "path", Explode: echopen.PtrTo(true), Schema: rgbObject()}, "/R=100,G=200", nil, rgb},
+		{"path_label", &v320.Parameter{In: "path", Style: "label", Schema: &v320.Schema{Type: v320.IntegerSchemaType}}, "/.3", nil, 3},
+		{"path_label_array", &v320.Parameter{In: "path", Style: "label", Schema: intArray()}, "/.3,4,5", nil, ints},
+		{"path_label_array_explode", &v320.Parameter{In: "path", Style: "label", Explode: echopen.PtrTo(true), Schema: intArray()}, "/.3.4.5", nil, ints},
+		{"path_matrix", &v320.Parameter{In: "path", Style: "matrix", Schema: &v320.Schema{Type: v320.IntegerSchemaType}}, "/;id=3", nil, 3},
+		{"path_matrix_array", &v320.Parameter{In: "path", Style: "matrix", Schema: intArray()}, "/;id=3,4,5", nil, ints},
+		{"path_matrix_array_explode", &v320.Parameter{In: "path", Style: "matrix", Explode: echopen.PtrTo(true), Schema: intArray()}, "/;id=3;id=4;id=5", nil, ints},
+		{"path_matrix_object_explode", &v320.Parameter{In: "path", Style: "matrix", Explode: echopen.PtrTo(true), Schema: rgbObject()}, "/;R=100;G=200", nil, rgb},
+		{"query_form_array", &v320.Parameter{In: "query", Schema: intArray()}, "/?id=3&id=4&id=5", nil, ints},
+		{"query_form_object", &v320.Parameter{In: "query", Schema: rgbObject()}, "/?R=100&G=200", nil, rgb},
+		{"query_form_array_no_explode", &v320.Parameter{In: "query", Explode: echopen.PtrTo(false), Schema: intArray()}, "/?id=3,4,5", nil, ints},
+		{"query_form_object_no_explode", &v320.Parameter{In: "query", Explode: echopen.PtrTo(false), Schema: rgbObject()}, "/?id=R,100,G,200", nil, rgb},
+		{"query_space_delimited", &v320.Parameter{In: "query", Style: "spaceDelimited", Schema: intArray()}, "/?id=3%204%205", nil, ints},
+		{"query_pipe_delimited", &v320.Parameter{In: "query", Style: "pipeDelimited", Schema: intArray()}, "/?id=3%7C4%7C5", nil, ints},
+		{"query_deep_object", &v320.Parameter{In: "query", Style: "deepObject", Schema: rgbObject()}, "/?id%5BR%5D=100&id%5BG%5D=200", nil, rgb},
+		{"query_deep_object_no_schema", &v320.Parameter{In: "query", Style: "deepObject"}, "/?id%5Ba%5D=1", nil, map[string]interface{}{"a": "1"}},
+		{"header_simple_array", &v320.Parameter{In: "header", Schema: intArray()}, "/", func(req *http.Request) {
+			req.Header.Add("Id", "3, 4")
+			req.Header.Add("Id", "5")
+		}, ints},
+		{"header_simple_object", &v320.Parameter{In: "header", Schema: rgbObject()}, "/", func(req *http.Request) {
+			req.Header.Add("Id", "R,100,G,200")
+		}, rgb},
+		{"cookie_form_array", &v320.Parameter{In: "cookie", Schema: intArray()}, "/", func(req *http.Request) {
+			req.AddCookie(&http.Cookie{Name: "id", Value: "3"})
+			req.AddCookie(&http.Cookie{Name: "id", Value: "4"})
+			req.AddCookie(&http.Cookie{Name: "id", Value: "5"})
+		}, ints},
+		{"cookie_form_object", &v320.Parameter{In: "cookie", Schema: rgbObject()}, "/", func(req *http.Request) {
+			req.AddCookie(&http.Cookie{Name: "R", Value: "100"})
+			req.AddCookie(&http.Cookie{Name: "G", Value: "200"})
+		}, rgb},
+		{"cookie_form_array_no_explode", &v320.Parameter{In: "cookie", Explode: echopen.PtrTo(false), Schema: intArray()}, "/", func(req *http.Request) {
+			req.AddCookie(&http.Cookie{Name: "id", Value: "3,4,5"})
+		}, ints},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Param.Name = "id"
+			if tc.Param.In == "header" {
+				tc.Param.Name = "Id"
+			}
+			tc.Param.Required = true
+
+			path := "/"
+			if tc.Param.In == "path" {
+				path = "/:id"
+			}
+
+			api := echopen.New("Test", "1.0.0")
+			api.GET(path, func(c echo.Context) error {
+				assert.Equal(t, tc.Expected, c.Get(string(tc.Param.In)+"."+tc.Param.Name))
+				return c.NoContent(http.StatusNoContent)
+			}, echopen.WithParameter(tc.Param))
+
+			req := httptest.NewRequest(http.MethodGet, tc.Target, nil)
+			if tc.Request != nil {
+				tc.Request(req)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusNoContent, res.Code)
+		})
+	}
+}
+
+func TestParameterStyleMalformed(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET("/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, echopen.WithParameter(&v320.Parameter{
+		Name:   "id",
+		In:     "path",
+		Style:  "matrix",
+		Schema: &v320.Schema{Type: v320.IntegerSchemaType},
+	}))
+
+	_, res := executeRequest(api, http.MethodGet, "/;id=3", nil)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+
+	_, res = executeRequest(api, http.MethodGet, "/3", nil)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	_, res = executeRequest(api, http.MethodGet, "/;other=3", nil)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	// Values present in the request but not matching the style are invalid rather than missing
+	var handled error
+	api.Engine.HTTPErrorHandler = func(err error, c echo.Context) {
+		handled = err
+		c.NoContent(http.StatusBadRequest)
+	}
+	api.GET("/object", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, echopen.WithParameter(&v320.Parameter{
+		Name:    "rgb",
+		In:      "query",
+		Explode: echopen.PtrTo(false),
+		Schema:  &v320.Schema{Type: v320.ObjectSchemaType},
+	}))
+
+	for _, target := range []string{"/3", "/object?rgb=R,100,G"} {
+		handled = nil
+		executeRequest(api, http.MethodGet, target, nil)
+		assert.ErrorIs(t, handled, echopen.ErrParameterInvalid, target)
+		assert.NotErrorIs(t, handled, echopen.ErrRequiredParameterMissing, target)
+	}
+
+	assert.Panics(t, func() {
+		echopen.WithParameter(&v320.Parameter{Name: "id", In: "header", Style: "form"})
+	})
+}
+
+func TestHeaderParameterAllowMultiple(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET("/", func(c echo.Context) error {
+		assert.Equal(t, []interface{}{1, 2, 3}, c.Get("header.X-Id"))
+		assert.Equal(t, 1, c.Get("header.X-First"))
+		return c.NoContent(http.StatusNoContent)
+	},
+		echopen.WithHeaderParameterConfig(&echopen.HeaderParameterConfig{
+			Name:          "X-Id",
+			AllowMultiple: true,
+			Schema:        &v320.Schema{Type: v320.IntegerSchemaType},
+		}),
+		echopen.WithHeaderParameterConfig(&echopen.HeaderParameterConfig{
+			Name:   "X-First",
+			Schema: &v320.Schema{Type: v320.IntegerSchemaType},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add("X-Id", "1")
+	req.Header.Add("X-Id", "2, 3")
+	req.Header.Add("X-First", "1")
+	req.Header.Add("X-First", "2")
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+
+	param := api.Spec.Paths["/"].Value.Get.Parameters[0].Value
+	assert.Equal(t, v320.ArraySchemaType, param.Schema.Type)
+	assert.Equal(t, v320.IntegerSchemaType, param.Schema.Items.Value.Type)
+}
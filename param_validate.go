@@ -0,0 +1,174 @@
+package echopen
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+)
+
+// ParameterError is returned when a parameter value does not satisfy a keyword of the parameter schema
+type ParameterError struct {
+	Name    string
+	In      v320.ParameterLocation
+	Keyword string
+}
+
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("echopen: %s parameter '%s' does not satisfy schema keyword '%s'", e.In, e.Name, e.Keyword)
+}
+
+func (e *ParameterError) Unwrap() error {
+	return ErrParameterInvalid
+}
+
+// Compiled schema patterns, shared across routes
+var schemaPatterns sync.Map
+
+func compileSchemaPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := schemaPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	schemaPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// validateParameter checks a converted parameter value against the constraints of the parameter schema
+func (r *RouteWrapper) validateParameter(param *v320.Parameter, val interface{}) error {
+	if kw := r.schemaKeywordFailed(param.Schema, val); kw != "" {
+		return &ParameterError{Name: param.Name, In: param.In, Keyword: kw}
+	}
+	return nil
+}
+
+// schemaKeywordFailed returns the first schema keyword not satisfied by the value, or an empty string if all are satisfied
+func (r *RouteWrapper) schemaKeywordFailed(s *v320.Schema, val interface{}) string {
+	if s == nil {
+		return ""
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if e == fmt.Sprint(val) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "enum"
+		}
+	}
+
+	switch v := val.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			return "minLength"
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return "maxLength"
+		}
+		if s.Pattern != "" {
+			re, err := compileSchemaPattern(s.Pattern)
+			if err != nil || !re.MatchString(v) {
+				return "pattern"
+			}
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return "minItems"
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return "maxItems"
+		}
+		if s.UniqueItems {
+			// Items such as []byte are not comparable so cannot be used as map keys
+			for i := range v {
+				for j := i + 1; j < len(v); j++ {
+					if reflect.DeepEqual(v[i], v[j]) {
+						return "uniqueItems"
+					}
+				}
+			}
+		}
+		if s.Items != nil {
+			items, _ := s.Items.DeRef(r.API.Spec.Components).(*v320.Schema)
+			for _, item := range v {
+				if kw := r.schemaKeywordFailed(items, item); kw != "" {
+					return kw
+				}
+			}
+		}
+
+	case map[string]interface{}:
+		if s.MinProperties != nil && len(v) < *s.MinProperties {
+			return "minProperties"
+		}
+		if s.MaxProperties != nil && len(v) > *s.MaxProperties {
+			return "maxProperties"
+		}
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return "required"
+			}
+		}
+		for name, pv := range v {
+			if ref, ok := s.Properties[name]; ok {
+				prop, _ := ref.DeRef(r.API.Spec.Components).(*v320.Schema)
+				if kw := r.schemaKeywordFailed(prop, pv); kw != "" {
+					return kw
+				}
+			}
+		}
+
+	default:
+		f, ok := toFloat64(val)
+		if !ok {
+			break
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return "minimum"
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return "maximum"
+		}
+		if s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum {
+			return "exclusiveMinimum"
+		}
+		if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+			return "exclusiveMaximum"
+		}
+		if s.MultipleOf != nil && *s.MultipleOf > 0 {
+			q := f / *s.MultipleOf
+			if math.Abs(q-math.Round(q)) > 1e-9 {
+				return "multipleOf"
+			}
+		}
+	}
+
+	return ""
+}
+
+// toFloat64 converts numeric values to float64 for comparison against numeric schema keywords
+func toFloat64(val interface{}) (float64, bool) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
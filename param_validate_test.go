@@ -0,0 +1,127 @@
+package echopen_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSchemaConstraints(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/:id",
+		func(c echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		},
+		echopen.WithPathParameterConfig(&echopen.PathParameterConfig{
+			Name: "id",
+			Schema: &v320.Schema{
+				Type:       v320.IntegerSchemaType,
+				Minimum:    echopen.PtrTo(10.0),
+				Maximum:    echopen.PtrTo(100.0),
+				MultipleOf: echopen.PtrTo(5.0),
+			},
+		}),
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name: "code",
+			Schema: &v320.Schema{
+				Type:      v320.StringSchemaType,
+				MinLength: echopen.PtrTo(2),
+				MaxLength: echopen.PtrTo(4),
+				Pattern:   "^[A-Z]+$",
+			},
+		}),
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name:   "sort",
+			Schema: &v320.Schema{Type: v320.StringSchemaType, Enum: []string{"asc", "desc"}},
+		}),
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name: "ids",
+			Schema: &v320.Schema{
+				Type:        v320.ArraySchemaType,
+				MaxItems:    echopen.PtrTo(2),
+				UniqueItems: true,
+				Items:       &v320.Ref[v320.Schema]{Value: &v320.Schema{Type: v320.IntegerSchemaType, ExclusiveMinimum: echopen.PtrTo(0.0)}},
+			},
+		}),
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name: "keys",
+			Schema: &v320.Schema{
+				Type:        v320.ArraySchemaType,
+				UniqueItems: true,
+				Items:       &v320.Ref[v320.Schema]{Value: &v320.Schema{Type: v320.StringSchemaType, Format: v320.ByteSchemaFormat}},
+			},
+		}),
+	)
+
+	type tcd struct {
+		Name      string
+		Target    string
+		Parameter string
+		Keyword   string
+	}
+
+	defs := []tcd{
+		{"valid", "/20?code=AB&sort=asc&ids=1&ids=2", "", ""},
+		{"minimum", "/5", "id", "minimum"},
+		{"maximum", "/105", "id", "maximum"},
+		{"multiple_of", "/21", "id", "multipleOf"},
+		{"min_length", "/20?code=A", "code", "minLength"},
+		{"max_length", "/20?code=ABCDE", "code", "maxLength"},
+		{"pattern", "/20?code=ab", "code", "pattern"},
+		{"enum", "/20?sort=up", "sort", "enum"},
+		{"max_items", "/20?ids=1&ids=2&ids=3", "ids", "maxItems"},
+		{"unique_items", "/20?ids=1&ids=1", "ids", "uniqueItems"},
+		{"item_exclusive_minimum", "/20?ids=0", "ids", "exclusiveMinimum"},
+		{"unique_bytes", "/20?keys=YQ==&keys=Yg==", "", ""},
+		{"unique_bytes_duplicate", "/20?keys=YQ==&keys=YQ==", "keys", "uniqueItems"},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.Target, nil)
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+
+			if tc.Keyword == "" {
+				assert.Equal(t, http.StatusNoContent, res.Code)
+				return
+			}
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			body := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			assert.Equal(t, tc.Parameter, body["parameter"])
+			assert.Equal(t, tc.Keyword, body["keyword"])
+		})
+	}
+}
+
+func TestParameterInvalidPattern(t *testing.T) {
+	assert.Panics(t, func() {
+		echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+			Name:   "code",
+			Schema: &v320.Schema{Type: v320.StringSchemaType, Pattern: "["},
+		})
+	})
+	type Query struct {
+		Code string `query:"code" pattern:"["`
+	}
+	type Header struct {
+		Code string `header:"X-Code" pattern:"["`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	assert.PanicsWithValue(t, "echopen: invalid pattern for parameter 'code': error parsing regexp: missing closing ]: `[`", func() {
+		api.GET("/query", nil, echopen.WithQueryStruct(Query{}))
+	})
+	assert.Panics(t, func() {
+		api.GET("/header", nil, echopen.WithHeaderStruct(Header{}))
+	})
+}
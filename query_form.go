@@ -2,12 +2,15 @@ package echopen
 
 import (
 	"fmt"
+	"mime/multipart"
 	"reflect"
 
 	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
 )
 
 // WithQueryStruct extracts type information from a provided struct to populate the OpenAPI operation parameters.
+// Fields of embedded structs, including pointers to structs, are flattened into the operation parameters.
 // A bound struct of the same type is added to the context under the key "query" during each request
 func WithQueryStruct(target interface{}) RouteConfigFunc {
 	t := reflect.TypeOf(target)
@@ -16,35 +19,81 @@ func WithQueryStruct(target interface{}) RouteConfigFunc {
 	}
 
 	return func(rw *RouteWrapper) *RouteWrapper {
-		s := rw.API.StructTypeToSchema(t, "query")
-		rw.QuerySchema = s
-
-		for name, prop := range s.Properties {
-			rw.Operation.AddParameter(&v320.Parameter{
-				Name:        name,
-				In:          "query",
-				Required:    false,
-				Description: prop.Value.Description,
-				Style:       "form",
-				Schema: &v320.Schema{
-					Type:    prop.Value.Type,
-					Items:   prop.Value.Items,
-					Enum:    prop.Value.Enum,
-					Default: prop.Value.Default,
-				},
-			})
-		}
+		rw.QuerySchema = rw.addStructParameters(v320.QueryParameter, parameterStructTags[v320.QueryParameter], t)
 
 		return rw
 	}
 }
 
 // WithFormStruct extracts type information from a provided struct to populate the OpenAPI operation parameters.
+// Fields are named by the form struct tag, and *multipart.FileHeader fields are documented as binary parts of a multipart form.
+// The encoding of each multipart part is populated from the contentType and headers struct tags.
 // A bound struct of the same type is added to the context under the key "form" during each request
 // Binding will use either request body or query params (GET/DELETE only)
 func WithFormStruct(target interface{}) RouteConfigFunc {
+	t := reflect.TypeOf(target)
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("echopen: struct expected, received %s", t.Kind()))
+	}
+
 	return func(rw *RouteWrapper) *RouteWrapper {
-		// TODO
+		if rw.formFromQuery() {
+			rw.FormSchema = rw.addStructParameters(v320.QueryParameter, "form", t)
+			return rw
+		}
+
+		s := rw.API.structFieldsSchema(t, "form")
+		rw.FormSchema = s
+
+		// Files can only be sent as parts of a multipart form
+		content := map[string]*v320.MediaTypeObject{
+			echo.MIMEMultipartForm: {Schema: v320.NewSchemaValue(s), Encoding: multipartEncoding(t)},
+		}
+		if !hasFileFields(t) {
+			content[echo.MIMEApplicationForm] = &v320.MediaTypeObject{Schema: v320.NewSchemaValue(s)}
+		}
+
+		// Add to any request body already declared for other media types
+		rw.addRequestBodyContent("", false, content)
+
 		return rw
 	}
 }
+
+// formFromQuery returns whether a form struct is bound from the query, as GET and DELETE requests have no body
+func (rw *RouteWrapper) formFromQuery() bool {
+	return rw.PathItem != nil && (rw.PathItem.Get == rw.Operation || rw.PathItem.Delete == rw.Operation)
+}
+
+// structFieldsSchema returns an object schema with a property for each field of the struct named by the tag.
+// Fields of embedded structs are flattened, and fields with a required validate tag are required.
+func (w *APIWrapper) structFieldsSchema(t reflect.Type, tag string) *v320.Schema {
+	s := &v320.Schema{
+		Type:       v320.ObjectSchemaType,
+		Properties: map[string]*v320.Ref[v320.Schema]{},
+		SourceType: t,
+	}
+	walkStructFields(t, tag, nil, func(f reflect.StructField, name string, _ []int) {
+		s.Properties[name] = w.StructFieldToSchemaRef(f)
+		if isRequiredField(f) {
+			s.Required = append(s.Required, name)
+		}
+	})
+	return s
+}
+
+// hasFileFields checks for *multipart.FileHeader fields, or slices of them, in the struct or embedded structs
+func hasFileFields(t reflect.Type) bool {
+	fileType := reflect.TypeOf(multipart.FileHeader{})
+	found := false
+	walkStructFields(t, "form", nil, func(f reflect.StructField, _ string, _ []int) {
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if ft == fileType {
+			found = true
+		}
+	})
+	return found
+}
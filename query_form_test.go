@@ -0,0 +1,149 @@
+package echopen_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteFormStruct(t *testing.T) {
+	type Form struct {
+		Name string   `form:"name" validate:"required"`
+		Age  int      `form:"age"`
+		Tags []string `form:"tags"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST(
+		"/",
+		func(c echo.Context) error {
+			form, err := echopen.Form[*Form](c)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Get("form"), form)
+			assert.Equal(t, "foo", form.Name)
+			assert.Equal(t, 42, form.Age)
+			assert.Equal(t, []string{"a", "b"}, form.Tags)
+			return c.NoContent(204)
+		},
+		echopen.WithFormStruct(Form{}),
+	)
+
+	rb := api.Spec.Paths["/"].Value.Post.RequestBody.Value
+	assert.Len(t, rb.Content, 2)
+	s := rb.Content[echo.MIMEApplicationForm].Schema.Value
+	assert.Equal(t, v320.ObjectSchemaType, s.Type)
+	assert.Equal(t, []string{"name"}, s.Required)
+	assert.Contains(t, s.Properties, "tags")
+	assert.Equal(t, s, rb.Content[echo.MIMEMultipartForm].Schema.Value)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=foo&age=42&tags=a&tags=b"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	// Validation failures are client errors, as they are for forms sent in the query
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("age=42"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 400, res.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"foo"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 415, res.Result().StatusCode)
+}
+
+func TestRouteFormStructMultipart(t *testing.T) {
+	type Upload struct {
+		Title string                  `form:"title"`
+		File  *multipart.FileHeader   `form:"file"`
+		Extra []*multipart.FileHeader `form:"extra"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST(
+		"/",
+		func(c echo.Context) error {
+			form, err := echopen.Form[*Upload](c)
+			assert.NoError(t, err)
+			assert.Equal(t, "doc", form.Title)
+			if assert.NotNil(t, form.File) {
+				assert.Equal(t, "a.txt", form.File.Filename)
+				f, _ := form.File.Open()
+				data, _ := io.ReadAll(f)
+				assert.Equal(t, "hello", string(data))
+			}
+			assert.Len(t, form.Extra, 2)
+			return c.NoContent(204)
+		},
+		echopen.WithFormStruct(Upload{}),
+	)
+
+	rb := api.Spec.Paths["/"].Value.Post.RequestBody.Value
+	assert.Len(t, rb.Content, 1)
+	s := rb.Content[echo.MIMEMultipartForm].Schema.Value
+	assert.Equal(t, v320.SchemaFormat("binary"), s.Properties["file"].Value.Format)
+	assert.Equal(t, v320.SchemaFormat("binary"), s.Properties["extra"].Value.Items.Value.Format)
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	_ = w.WriteField("title", "doc")
+	fw, _ := w.CreateFormFile("file", "a.txt")
+	_, _ = fw.Write([]byte("hello"))
+	for _, name := range []string{"b.txt", "c.txt"} {
+		fw, _ = w.CreateFormFile("extra", name)
+		_, _ = fw.Write([]byte(name))
+	}
+	_ = w.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, 204, res.Result().StatusCode)
+}
+
+func TestRouteFormStructQuery(t *testing.T) {
+	type Form struct {
+		Name  string `form:"name" validate:"required"`
+		Limit int    `form:"limit"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			form, err := echopen.Form[Form](c)
+			assert.NoError(t, err)
+			assert.Equal(t, Form{Name: "foo", Limit: 10}, form)
+			return c.NoContent(204)
+		},
+		echopen.WithFormStruct(Form{}),
+	)
+
+	op := api.Spec.Paths["/"].Value.Get
+	assert.Nil(t, op.RequestBody)
+	if assert.Len(t, op.Parameters, 2) {
+		assert.Equal(t, "name", op.Parameters[0].Value.Name)
+		assert.Equal(t, v320.QueryParameter, op.Parameters[0].Value.In)
+		assert.True(t, op.Parameters[0].Value.Required)
+	}
+
+	_, res := executeRequest(api, http.MethodGet, "/?name=foo&limit=10", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/?limit=10", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
@@ -31,6 +31,10 @@ func (w *APIWrapper) TypeToSchemaRef(typ reflect.Type) *v320.Ref[v320.Schema] {
 		return w.TypeToSchemaRef(typ.Elem())
 	} else if typ.Kind() == reflect.Struct {
 		name := typ.Name()
+		if typ == reflect.TypeOf(multipart.FileHeader{}) {
+			// Files are binary strings rather than components
+			return &v320.Ref[v320.Schema]{Value: w.TypeToSchema(typ)}
+		}
 		if name != "" { // named struct → component
 			if ref, ok := w.schemaMap[typ]; ok {
 				return &v320.Ref[v320.Schema]{Ref: ref}
@@ -220,6 +224,10 @@ func (w *APIWrapper) StructFieldToSchemaRef(f reflect.StructField) *v320.Ref[v32
 		if enum := getEchoTag(f, "enum"); enum != "" {
 			ref.Value.Enum = strings.Split(enum, ",")
 		}
+		if pattern := getEchoTag(f, "pattern"); pattern != "" {
+			ref.Value.Pattern = pattern
+		}
+		ref.Value.Deprecated = getEchoTag(f, "deprecated") == "true"
 		ExtractValidationRules(f, ref.Value)
 		if example := getEchoTag(f, "example"); example != "" {
 			ref.Value.Examples = append(ref.Value.Examples, example)
@@ -242,6 +250,13 @@ func (w *APIWrapper) StructFieldToSchemaRef(f reflect.StructField) *v320.Ref[v32
 			ref.Value.Enum = strings.Split(enum, ",")
 		}
 
+		pattern := getEchoTag(f, "pattern")
+		if pattern != "" {
+			ref.Value.Pattern = pattern
+		}
+
+		ref.Value.Deprecated = getEchoTag(f, "deprecated") == "true"
+
 		// Nullable support: represent as oneOf [<original>, null]
 		if n := getEchoTag(f, "nullable"); n == "true" {
 			// If field resolved to a $ref, wrap it into a oneOf with null
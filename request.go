@@ -0,0 +1,129 @@
+package echopen
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+)
+
+// Context key for the request data bound by the route middleware
+const requestDataKey = "echopen.request"
+
+// RequestData holds the values bound from a request by the route middleware.
+// Values are also added to the context under the keys described in the README for compatibility.
+type RequestData struct {
+	// Bound request body struct
+	Body interface{}
+	// Bound query struct
+	Query interface{}
+	// Bound form struct
+	Form interface{}
+	// Individually declared parameter values by location and name
+	Params map[v320.ParameterLocation]map[string]interface{}
+	// Bound path, header, and cookie structs by location
+	Structs map[v320.ParameterLocation]interface{}
+}
+
+// GetRequestData returns the request data bound to the context, creating it if not present
+func GetRequestData(c echo.Context) *RequestData {
+	if rd, ok := c.Get(requestDataKey).(*RequestData); ok {
+		return rd
+	}
+	rd := &RequestData{
+		Params:  map[v320.ParameterLocation]map[string]interface{}{},
+		Structs: map[v320.ParameterLocation]interface{}{},
+	}
+	c.Set(requestDataKey, rd)
+	return rd
+}
+
+// setParameter adds a parameter value to the request data and the context key <in>.<name>
+func setParameter(c echo.Context, param *v320.Parameter, val interface{}) {
+	rd := GetRequestData(c)
+	if rd.Params[param.In] == nil {
+		rd.Params[param.In] = map[string]interface{}{}
+	}
+	rd.Params[param.In][param.Name] = val
+	c.Set(fmt.Sprintf("%s.%s", param.In, param.Name), val)
+}
+
+// Body returns the bound request body, as either the struct type given to WithRequestBodyStruct or a pointer to it
+func Body[T any](c echo.Context) (T, error) {
+	return boundValue[T](GetRequestData(c).Body, "request body")
+}
+
+// Query returns the bound query struct, as either the struct type given to WithQueryStruct or a pointer to it
+func Query[T any](c echo.Context) (T, error) {
+	return boundValue[T](GetRequestData(c).Query, "query struct")
+}
+
+// Form returns the bound form struct, as either the struct type given to WithFormStruct or a pointer to it
+func Form[T any](c echo.Context) (T, error) {
+	return boundValue[T](GetRequestData(c).Form, "form struct")
+}
+
+// PathStruct returns the bound path struct, as either the struct type given to WithPathStruct or a pointer to it
+func PathStruct[T any](c echo.Context) (T, error) {
+	return boundValue[T](GetRequestData(c).Structs[v320.PathParameter], "path struct")
+}
+
+// HeaderStruct returns the bound header struct, as either the struct type given to WithHeaderStruct or a pointer to it
+func HeaderStruct[T any](c echo.Context) (T, error) {
+	return boundValue[T](GetRequestData(c).Structs[v320.HeaderParameter], "header struct")
+}
+
+// CookieStruct returns the bound cookie struct, as either the struct type given to WithCookieStruct or a pointer to it
+func CookieStruct[T any](c echo.Context) (T, error) {
+	return boundValue[T](GetRequestData(c).Structs[v320.CookieParameter], "cookie struct")
+}
+
+// PathParam returns the named path parameter converted to T
+func PathParam[T any](c echo.Context, name string) (T, error) {
+	return boundParameter[T](c, v320.PathParameter, name)
+}
+
+// QueryParam returns the named individually declared query parameter converted to T
+func QueryParam[T any](c echo.Context, name string) (T, error) {
+	return boundParameter[T](c, v320.QueryParameter, name)
+}
+
+// Header returns the named header parameter converted to T.
+// The name is converted to the canonical header key.
+func Header[T any](c echo.Context, name string) (T, error) {
+	return boundParameter[T](c, v320.HeaderParameter, http.CanonicalHeaderKey(name))
+}
+
+// Cookie returns the named cookie parameter converted to T
+func Cookie[T any](c echo.Context, name string) (T, error) {
+	return boundParameter[T](c, v320.CookieParameter, name)
+}
+
+func boundParameter[T any](c echo.Context, in v320.ParameterLocation, name string) (T, error) {
+	return boundValue[T](GetRequestData(c).Params[in][name], fmt.Sprintf("%s parameter '%s'", in, name))
+}
+
+// boundValue converts a bound value to T.
+// Pointers to structs are dereferenced, numeric values converted, and []interface{} values converted to typed slices.
+func boundValue[T any](val interface{}, desc string) (T, error) {
+	var zero T
+	if val == nil {
+		return zero, fmt.Errorf("%w: %s", ErrValueNotBound, desc)
+	}
+	if v, ok := val.(T); ok {
+		return v, nil
+	}
+
+	out := reflect.New(reflect.TypeOf(&zero).Elem()).Elem()
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Type().AssignableTo(out.Type()) {
+		out.Set(rv.Elem())
+		return out.Interface().(T), nil
+	}
+	if err := setFieldValue(out, val); err != nil {
+		return zero, fmt.Errorf("%w: %s: %w", ErrValueTypeMismatch, desc, err)
+	}
+	return out.Interface().(T), nil
+}
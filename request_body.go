@@ -1,59 +1,214 @@
 package echopen
 
 import (
+	"encoding/xml"
 	"fmt"
+	"io"
+	"net/http"
 	"reflect"
+	"strings"
 
 	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
 )
 
-// WithRequestBodyStruct extracts type information from a provided struct to populate the OpenAPI requestBody.
-// A bound struct of the same type is added to the context under the key "body" during each request.
-func WithRequestBodyStruct(mime string, description string, target interface{}) RouteConfigFunc {
-	t := reflect.TypeOf(target)
+// BodyDecoder decodes a request body into the target value
+type BodyDecoder func(r io.Reader, v interface{}) error
+
+// Media types decoded by the echo binder
+var builtinBodyDecoders = map[string]bool{
+	echo.MIMEApplicationJSON: true,
+	echo.MIMEApplicationXML:  true,
+	echo.MIMETextXML:         true,
+	echo.MIMEApplicationForm: true,
+	echo.MIMEMultipartForm:   true,
+}
+
+type RequestBodyStructConfig struct {
+	Description string
+	Target      interface{}
+	MediaTypes  []string
+	Required    bool
+}
+
+// WithRequestBodyStructConfig extracts type information from a provided struct to populate the OpenAPI requestBody for each media type.
+// Media types other than JSON, XML, forms, and types with a +json or +xml suffix must have a decoder registered with WithBodyDecoder, or it will panic.
+// Media ranges such as application/* are decoded according to the request content type.
+// A bound struct of the same type, decoded according to the request content type, is added to the context under the key "body" during each request.
+func WithRequestBodyStructConfig(config *RequestBodyStructConfig) RouteConfigFunc {
+	t := reflect.TypeOf(config.Target)
 	if t.Kind() != reflect.Struct {
 		panic(fmt.Errorf("echopen: struct expected, received %s", t.Kind()))
 	}
 
 	return func(rw *RouteWrapper) *RouteWrapper {
-		s := rw.API.ToSchemaRef(target)
-		rw.RequestBodySchema[mime] = s.DeRef(rw.API.Spec.Components).(*v320.Schema)
-
-		// rw.Middlewares = append(rw.Middlewares, func(next echo.HandlerFunc) echo.HandlerFunc {
-		// 	val := validator.New(validator.WithRequiredStructEnabled())
-
-		// 	return func(c echo.Context) error {
-		// 		// Create a new struct of the given type
-		// 		v := reflect.New(t).Interface()
-
-		// 		// Bind the struct to the body
-		// 		if err := (&echo.DefaultBinder{}).BindBody(c, v); err != nil {
-		// 			return err
-		// 		}
+		s := rw.API.ToSchemaRef(config.Target)
+		content := map[string]*v320.MediaTypeObject{}
+		for _, mime := range config.MediaTypes {
+			if !rw.API.hasBodyDecoder(mime) {
+				panic(fmt.Sprintf("echopen: no decoder registered for content type %s", mime))
+			}
+			rw.RequestBodySchema[mime] = s.DeRef(rw.API.Spec.Components).(*v320.Schema)
+			content[mime] = &v320.MediaTypeObject{Schema: s}
+		}
 
-		// 		// Validate the bound struct
-		// 		if err := val.StructCtx(c.Request().Context(), v); err != nil {
-		// 			return err
-		// 		}
+		rw.addRequestBodyContent(config.Description, config.Required, content)
 
-		// 		// Add to context
-		// 		c.Set("body", v)
+		return rw
+	}
+}
 
-		// 		return next(c)
-		// 	}
-		// })
+// WithRequestBodyStruct extracts type information from a provided struct to populate the OpenAPI requestBody.
+// A bound struct of the same type is added to the context under the key "body" during each request.
+// Calling again with another media type adds to the same requestBody.
+func WithRequestBodyStruct(mime string, description string, target interface{}) RouteConfigFunc {
+	return WithRequestBodyStructConfig(&RequestBodyStructConfig{
+		Description: description,
+		Target:      target,
+		MediaTypes:  []string{mime},
+	})
+}
 
+// addRequestBodyContent adds media types to the operation requestBody, creating it if not present.
+// The requestBody is required if any declaration requires it.
+func (rw *RouteWrapper) addRequestBodyContent(description string, required bool, content map[string]*v320.MediaTypeObject) {
+	rb := rw.Operation.RequestBody
+	if rb == nil || rb.Value == nil {
 		rw.Operation.AddRequestBody(&v320.RequestBody{
 			Description: description,
-			Content: map[string]*v320.MediaTypeObject{
-				mime: {Schema: s},
-			},
+			Required:    required,
+			Content:     content,
 		})
+		return
+	}
 
+	if description != "" {
+		rb.Value.Description = description
+	}
+	if required {
+		rb.Value.Required = true
+	}
+	if rb.Value.Content == nil {
+		rb.Value.Content = map[string]*v320.MediaTypeObject{}
+	}
+	for mime, mt := range content {
+		rb.Value.Content[mime] = mt
+	}
+}
+
+// WithRequestBodyRequired marks the requestBody declared by a previous config function as required or optional.
+// Requests to routes with a required body and no body respond with ErrRequestBodyMissing, while binding is skipped for absent optional bodies.
+// Panics if no requestBody has been declared, or it is a reference to a component.
+func WithRequestBodyRequired(required bool) RouteConfigFunc {
+	return func(rw *RouteWrapper) *RouteWrapper {
+		if rw.Operation.RequestBody == nil || rw.Operation.RequestBody.Value == nil {
+			panic("echopen: request body must be declared before setting required")
+		}
+		rw.Operation.RequestBody.Value.Required = required
 		return rw
 	}
 }
 
+// requestBodyRequired returns whether the declared requestBody is required
+func (r *RouteWrapper) requestBodyRequired() bool {
+	if r.Operation.RequestBody == nil {
+		return false
+	}
+	rb, _ := r.Operation.RequestBody.DeRef(r.API.Spec.Components).(*v320.RequestBody)
+	return rb != nil && rb.Required
+}
+
+// requestBodyMediaTypes returns the media types and ranges declared for the request body, including forms
+func (r *RouteWrapper) requestBodyMediaTypes() []string {
+	declared := []string{}
+	for mime := range r.RequestBodySchema {
+		declared = append(declared, mime)
+	}
+	if r.FormSchema != nil && !r.formFromQuery() {
+		for _, mime := range []string{echo.MIMEApplicationForm, echo.MIMEMultipartForm} {
+			if _, ok := r.RequestBodySchema[mime]; !ok {
+				declared = append(declared, mime)
+			}
+		}
+	}
+	return declared
+}
+
+// hasBodyDecoder returns whether request bodies of the declared media type can be decoded.
+// Media ranges are decoded according to the request content type, so cannot be checked in advance.
+func (w *APIWrapper) hasBodyDecoder(declared string) bool {
+	if strings.Contains(declared, "*") {
+		return true
+	}
+	d, ok := parseMediaRange(declared)
+	if !ok {
+		return false
+	}
+	_, registered := w.bodyDecoders[d.String()]
+	return registered || builtinBodyDecoders[d.String()] || d.suffix() == "json" || d.suffix() == "xml"
+}
+
+// bodyDecoderMediaType returns the media type of the decoder for a request body matching a declared media type or range.
+// A decoder for the request media type is preferred, then the declared media type, then either structured syntax suffix.
+func (w *APIWrapper) bodyDecoderMediaType(declared string, contentType string) string {
+	m, _ := parseMediaRange(contentType)
+	candidates := []mediaRange{m}
+	if d, ok := parseMediaRange(declared); ok && !strings.Contains(declared, "*") {
+		candidates = append(candidates, d)
+	}
+
+	for _, c := range candidates {
+		if _, ok := w.bodyDecoders[c.String()]; ok || builtinBodyDecoders[c.String()] {
+			return c.String()
+		}
+	}
+	for _, c := range candidates {
+		switch c.suffix() {
+		case "json":
+			return echo.MIMEApplicationJSON
+		case "xml":
+			return echo.MIMEApplicationXML
+		}
+	}
+	return m.String()
+}
+
+// bindBody decodes the request body into the target using a registered decoder for the media type, or the echo binder
+func (w *APIWrapper) bindBody(c echo.Context, mime string, v interface{}) error {
+	req := c.Request()
+	decode, ok := w.bodyDecoders[mime]
+	if !ok {
+		switch mime {
+		case echo.MIMEApplicationJSON:
+			// Decoded directly rather than by the echo binder, which requires an exact Content-Type
+			if !hasRequestBody(req) {
+				return nil
+			}
+			if err := c.Echo().JSONSerializer.Deserialize(c, v); err != nil {
+				if he, ok := err.(*echo.HTTPError); ok {
+					return he
+				}
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			return nil
+		case echo.MIMEApplicationXML, echo.MIMETextXML:
+			decode = func(r io.Reader, v interface{}) error {
+				return xml.NewDecoder(r).Decode(v)
+			}
+		default:
+			return (&echo.DefaultBinder{}).BindBody(c, v)
+		}
+	}
+
+	if !hasRequestBody(req) {
+		return nil
+	}
+	if err := decode(req.Body, v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
 func WithRequestBody(rb *v320.RequestBody) RouteConfigFunc {
 	return func(rw *RouteWrapper) *RouteWrapper {
 		for mime, content := range rb.Content {
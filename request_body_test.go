@@ -0,0 +1,241 @@
+package echopen_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRequestBodyMediaTypes(t *testing.T) {
+	type Body struct {
+		Name  string `json:"name" xml:"name" yaml:"name" form:"name"`
+		Count int    `json:"count" xml:"count" yaml:"count" form:"count"`
+	}
+
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithBodyDecoder("application/yaml", func(r io.Reader, v interface{}) error {
+			return yaml.NewDecoder(r).Decode(v)
+		}),
+	)
+	api.POST(
+		"/",
+		func(c echo.Context) error {
+			body, err := echopen.Body[Body](c)
+			assert.NoError(t, err)
+			assert.Equal(t, Body{Name: "foo", Count: 3}, body)
+			return c.NoContent(204)
+		},
+		echopen.WithRequestBodyStructConfig(&echopen.RequestBodyStructConfig{
+			Description: "Body",
+			Target:      Body{},
+			MediaTypes:  []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML, echo.MIMEApplicationForm, "application/yaml"},
+		}),
+	)
+
+	rb := api.Spec.Paths["/"].Value.Post.RequestBody.Value
+	assert.Equal(t, "Body", rb.Description)
+	assert.Len(t, rb.Content, 4)
+	assert.Equal(t, rb.Content[echo.MIMEApplicationJSON].Schema, rb.Content["application/yaml"].Schema)
+
+	type tcd struct {
+		Mime     string
+		Body     string
+		Expected int
+	}
+
+	defs := []tcd{
+		{echo.MIMEApplicationJSON, `{"name":"foo","count":3}`, 204},
+		{echo.MIMEApplicationXML, `<Body><name>foo</name><count>3</count></Body>`, 204},
+		{echo.MIMEApplicationForm, `name=foo&count=3`, 204},
+		{"application/yaml", "name: foo\ncount: 3\n", 204},
+		{"application/yaml", "name: [", 400},
+	}
+
+	for _, d := range defs {
+		t.Run(d.Mime, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(d.Body))
+			req.Header.Set(echo.HeaderContentType, d.Mime)
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, d.Expected, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestRequestBodyStructMerge(t *testing.T) {
+	type Body struct {
+		Name string `json:"name"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST(
+		"/",
+		func(c echo.Context) error { return c.NoContent(204) },
+		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "Body", Body{}),
+		echopen.WithRequestBodyStruct(echo.MIMEApplicationXML, "", Body{}),
+	)
+
+	rb := api.Spec.Paths["/"].Value.Post.RequestBody.Value
+	assert.Equal(t, "Body", rb.Description)
+	assert.Len(t, rb.Content, 2)
+}
+
+func TestRequestBodyDecoderMissing(t *testing.T) {
+	type Body struct {
+		Name string `json:"name"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	assert.PanicsWithValue(t, "echopen: no decoder registered for content type application/msgpack", func() {
+		api.POST(
+			"/",
+			func(c echo.Context) error { return c.NoContent(204) },
+			echopen.WithRequestBodyStruct("application/msgpack", "Body", Body{}),
+		)
+	})
+}
+
+func TestRequestBodyMediaTypeMatching(t *testing.T) {
+	type JSONBody struct {
+		Name string `json:"name"`
+	}
+	type V2Body struct {
+		Title string `json:"title"`
+	}
+	type XMLBody struct {
+		Name string `xml:"name"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST(
+		"/",
+		func(c echo.Context) error {
+			body := c.Get("body")
+			if body == nil {
+				return c.String(200, "none")
+			}
+			return c.String(200, fmt.Sprintf("%T %v", body, body))
+		},
+		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "", JSONBody{}),
+		echopen.WithRequestBodyStruct("application/vnd.acme+json; version=2", "", V2Body{}),
+		echopen.WithRequestBodyStruct("application/*", "", XMLBody{}),
+	)
+
+	type tcd struct {
+		Name         string
+		ContentTypes []string
+		Body         string
+		Status       int
+		Expected     string
+	}
+
+	defs := []tcd{
+		{"exact", []string{"application/json"}, `{"name":"a"}`, 200, "*echopen_test.JSONBody &{a}"},
+		{"charset", []string{"application/json; charset=UTF-8"}, `{"name":"a"}`, 200, "*echopen_test.JSONBody &{a}"},
+		{"case", []string{"Application/JSON"}, `{"name":"a"}`, 200, "*echopen_test.JSONBody &{a}"},
+		{"suffix", []string{"application/merge-patch+json"}, `{"name":"a"}`, 200, "*echopen_test.JSONBody &{a}"},
+		{"parameter", []string{"application/vnd.acme+json; version=2"}, `{"title":"b"}`, 200, "*echopen_test.V2Body &{b}"},
+		{"parameter_mismatch", []string{"application/vnd.acme+json; version=1"}, `{"name":"a"}`, 200, "*echopen_test.JSONBody &{a}"},
+		{"range", []string{"application/xml"}, `<XMLBody><name>c</name></XMLBody>`, 200, "*echopen_test.XMLBody &{c}"},
+		{"range_suffix", []string{"application/atom+xml"}, `<XMLBody><name>c</name></XMLBody>`, 200, "*echopen_test.XMLBody &{c}"},
+		{"undeclared", []string{"text/plain"}, `a`, 415, ""},
+		{"invalid", []string{"json"}, `a`, 415, ""},
+		{"none_empty", nil, ``, 200, "none"},
+		{"none_with_body", nil, `a`, 415, ""},
+		{"repeated", []string{"application/json", "application/json"}, `{"name":"a"}`, 200, "*echopen_test.JSONBody &{a}"},
+		{"conflicting", []string{"application/json", "application/xml"}, `{"name":"a"}`, 415, ""},
+	}
+
+	for _, d := range defs {
+		t.Run(d.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(d.Body))
+			for _, ct := range d.ContentTypes {
+				req.Header.Add(echo.HeaderContentType, ct)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, d.Status, res.Result().StatusCode)
+			if d.Expected != "" {
+				assert.Equal(t, d.Expected, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestBodyRequired(t *testing.T) {
+	type Body struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	handler := func(c echo.Context) error {
+		if c.Get("body") == nil {
+			return c.String(200, "none")
+		}
+		return c.String(200, c.Get("body").(*Body).Name)
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST("/required", handler, echopen.WithRequestBodyStructConfig(&echopen.RequestBodyStructConfig{
+		Target:     Body{},
+		MediaTypes: []string{echo.MIMEApplicationJSON},
+		Required:   true,
+	}))
+	api.POST("/optional", handler, echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "", Body{}))
+	api.POST("/override", handler,
+		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "", Body{}),
+		echopen.WithRequestBodyRequired(true),
+	)
+
+	assert.True(t, api.Spec.Paths["/required"].Value.Post.RequestBody.Value.Required)
+	assert.False(t, api.Spec.Paths["/optional"].Value.Post.RequestBody.Value.Required)
+	assert.True(t, api.Spec.Paths["/override"].Value.Post.RequestBody.Value.Required)
+
+	type tcd struct {
+		Path     string
+		Mime     string
+		Body     string
+		Status   int
+		Expected string
+	}
+
+	defs := []tcd{
+		{"/required", echo.MIMEApplicationJSON, `{"name":"a"}`, 200, "a"},
+		{"/required", echo.MIMEApplicationJSON, ``, 400, ""},
+		{"/required", echo.MIMEApplicationJSON, `{}`, 400, ""},
+		{"/optional", echo.MIMEApplicationJSON, `{}`, 400, ""},
+		{"/required", "", ``, 400, ""},
+		{"/override", "", ``, 400, ""},
+		{"/optional", echo.MIMEApplicationJSON, `{"name":"a"}`, 200, "a"},
+		{"/optional", echo.MIMEApplicationJSON, ``, 200, "none"},
+		{"/optional", "", ``, 200, "none"},
+	}
+
+	for _, d := range defs {
+		t.Run(d.Path+"_"+d.Mime+"_"+d.Body, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, d.Path, strings.NewReader(d.Body))
+			if d.Mime != "" {
+				req.Header.Set(echo.HeaderContentType, d.Mime)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, d.Status, res.Result().StatusCode)
+			if d.Expected != "" {
+				assert.Equal(t, d.Expected, res.Body.String())
+			}
+		})
+	}
+
+	assert.PanicsWithValue(t, "echopen: request body must be declared before setting required", func() {
+		api.POST("/none", handler, echopen.WithRequestBodyRequired(true))
+	})
+}
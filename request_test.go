@@ -0,0 +1,124 @@
+package echopen_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedAccessors(t *testing.T) {
+	type QueryStruct struct {
+		Limit int `query:"limit"`
+	}
+	type PathStruct struct {
+		ID int `param:"id"`
+	}
+	type Body struct {
+		Name string `json:"name"`
+	}
+
+	api := echopen.New("Test", "1.0.0")
+	api.POST(
+		"/:id",
+		func(c echo.Context) error {
+			id, err := echopen.PathParam[int](c, "id")
+			assert.NoError(t, err)
+			assert.Equal(t, 42, id)
+
+			id64, err := echopen.PathParam[int64](c, "id")
+			assert.NoError(t, err)
+			assert.Equal(t, int64(42), id64)
+
+			_, err = echopen.PathParam[string](c, "id")
+			assert.ErrorIs(t, err, echopen.ErrValueTypeMismatch)
+
+			_, err = echopen.PathParam[int](c, "other")
+			assert.ErrorIs(t, err, echopen.ErrValueNotBound)
+			assert.Contains(t, err.Error(), "path parameter 'other'")
+
+			path, err := echopen.PathStruct[PathStruct](c)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, path.ID)
+
+			qry, err := echopen.Query[*QueryStruct](c)
+			assert.NoError(t, err)
+			assert.Equal(t, 10, qry.Limit)
+
+			body, err := echopen.Body[Body](c)
+			assert.NoError(t, err)
+			assert.Equal(t, "test", body.Name)
+
+			ids, err := echopen.Header[[]int](c, "x-ids")
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1, 2}, ids)
+
+			theme, err := echopen.Cookie[string](c, "theme")
+			assert.NoError(t, err)
+			assert.Equal(t, "dark", theme)
+
+			_, err = echopen.HeaderStruct[PathStruct](c)
+			assert.ErrorIs(t, err, echopen.ErrValueNotBound)
+
+			return c.NoContent(http.StatusNoContent)
+		},
+		echopen.WithPathStruct(PathStruct{}),
+		echopen.WithQueryStruct(QueryStruct{}),
+		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "Body", Body{}),
+		echopen.WithHeaderParameterConfig(&echopen.HeaderParameterConfig{
+			Name:   "X-Ids",
+			Schema: &v320.Schema{Type: v320.ArraySchemaType, Items: &v320.Ref[v320.Schema]{Value: &v320.Schema{Type: v320.IntegerSchemaType}}},
+		}),
+		echopen.WithCookieParameter("theme", "Theme", ""),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/42?limit=10", strings.NewReader(`{"name":"test"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Ids", "1,2")
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+}
+
+func TestTypedAccessorsNumberRange(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/:id",
+		func(c echo.Context) error {
+			_, err := echopen.PathParam[int8](c, "id")
+			assert.ErrorIs(t, err, echopen.ErrValueTypeMismatch)
+
+			_, err = echopen.PathParam[uint](c, "id")
+			assert.ErrorIs(t, err, echopen.ErrValueTypeMismatch)
+
+			id, err := echopen.PathParam[int16](c, "id")
+			assert.NoError(t, err)
+			assert.Equal(t, int16(-300), id)
+
+			_, err = echopen.QueryParam[int](c, "f")
+			assert.ErrorIs(t, err, echopen.ErrValueTypeMismatch)
+
+			f, err := echopen.QueryParam[float32](c, "f")
+			assert.NoError(t, err)
+			assert.Equal(t, float32(2.9), f)
+
+			w, err := echopen.QueryParam[uint8](c, "w")
+			assert.NoError(t, err)
+			assert.Equal(t, uint8(3), w)
+
+			return c.NoContent(http.StatusNoContent)
+		},
+		echopen.WithPathParameter("id", "ID", int(0)),
+		echopen.WithQueryParameter("f", "Fraction", float64(0)),
+		echopen.WithQueryParameter("w", "Whole", float64(0)),
+	)
+
+	_, res := executeRequest(api, http.MethodGet, "/-300?f=2.9&w=3", nil)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+}
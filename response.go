@@ -20,7 +20,7 @@ type ResponseHeaderConfig struct {
 	Required    bool
 	Examples    []*v320.Example
 	Style       string
-	Explode     bool
+	Explode     *bool
 	Schema      *v320.Schema
 }
 
@@ -145,7 +145,9 @@ func WithResponseHeaderConfig(code string, cfg *ResponseHeaderConfig) RouteConfi
 			if cfg.Style != "" {
 				existing.Value.Style = cfg.Style
 			}
-			existing.Value.Explode = cfg.Explode
+			if cfg.Explode != nil {
+				existing.Value.Explode = cfg.Explode
+			}
 			return rw
 		}
 
@@ -2,9 +2,7 @@ package echopen
 
 import (
 	"fmt"
-	"net/http"
 	"reflect"
-	"strings"
 
 	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
 	"github.com/go-playground/validator/v10"
@@ -20,8 +18,10 @@ type RouteWrapper struct {
 	Middlewares       []echo.MiddlewareFunc
 	Route             *echo.Route
 	QuerySchema       *v320.Schema
+	ParameterSchemas  map[v320.ParameterLocation]*v320.Schema
 	FormSchema        *v320.Schema
 	RequestBodySchema map[string]*v320.Schema
+	MultipartConfig   *MultipartBodyConfig
 }
 
 // Operation validation middleware that is applied to all routes
@@ -33,86 +33,134 @@ func (r *RouteWrapper) middleware() echo.MiddlewareFunc {
 			// --------------------------------------------------------------------------------
 			// Check security requirements have been met, if specified
 			// --------------------------------------------------------------------------------
-			securityReqsMet := len(r.Operation.Security) == 0
-
-			for _, req := range r.Operation.Security {
-				if len(*req) == 0 {
-					// Empty object makes all requirements optional
-					securityReqsMet = true
-				} else {
-					for name, scopes := range *req {
-						scheme := r.API.Spec.GetComponents().GetSecurityScheme(name)
-						if scheme == nil {
-							// Scheme existence is checked at the point the requirement is added
-							continue
-						}
-
-						switch scheme.In {
-						case "header":
-							val, ok := c.Request().Header[http.CanonicalHeaderKey(scheme.Name)]
-							if ok && len(val) > 0 {
-								c.Set(fmt.Sprintf("security.%s", name), val[0])
-								c.Set(fmt.Sprintf("security.%s.scopes", name), scopes)
-								securityReqsMet = true
-							}
-						default:
-							panic("not implemented")
-						}
-					}
-				}
-			}
-
-			if !securityReqsMet {
-				return ErrSecurityRequirementsNotMet
+			if err := r.checkSecurity(c); err != nil {
+				return err
 			}
 
 			// --------------------------------------------------------------------------------
-			// Extract path, header, and cookie parameters (query dealt with as a struct)
+			// Extract path, header, cookie, and individually declared query parameters
 			// --------------------------------------------------------------------------------
 			for _, ref := range r.Operation.Parameters {
 				param := ref.DeRef(r.API.Spec.Components).(*v320.Parameter)
 
+				// Parameters with content are serialized documents rather than styled values
+				if len(param.Content) > 0 {
+					v, found, err := r.bindContentParameter(c, val, param)
+					if err != nil {
+						return err
+					}
+					if !found {
+						if err := r.missingParameter(c, param); err != nil {
+							return err
+						}
+						continue
+					}
+					setParameter(c, param, v)
+					continue
+				}
+
 				switch param.In {
 				case "path":
 					v := c.Param(param.Name)
 					if v == "" {
 						return ErrRequiredParameterMissing
 					}
-					val := param.Schema.FromString(v)
-					if val == nil {
-						return ErrRequiredParameterMissing
+					raw, ok := decodePathParameter(param, v)
+					if !ok {
+						return malformedParameter(param)
+					}
+					val, err := r.convertParameter(param.Schema, raw)
+					if err != nil {
+						return invalidParameter(param, err)
 					}
-					c.Set(fmt.Sprintf("path.%s", param.Name), val)
+					if err := r.validateParameter(param, val); err != nil {
+						return err
+					}
+					setParameter(c, param, val)
 
 				case "header":
 					v := c.Request().Header[param.Name]
 					if len(v) == 0 {
-						return ErrRequiredParameterMissing
-					}
-					if param.Schema.Type == "array" {
-						hdrs := []interface{}{}
-						for _, h := range v {
-							hdrs = append(hdrs, param.Schema.Items.DeRef(r.API.Spec.Components).(*v320.Schema).FromString(h))
+						if err := r.missingParameter(c, param); err != nil {
+							return err
 						}
-						c.Set(fmt.Sprintf("header.%s", param.Name), hdrs)
-					} else {
-						val := param.Schema.FromString(v[0])
-						if val == nil {
-							return ErrRequiredParameterMissing
-						}
-						c.Set(fmt.Sprintf("header.%s", param.Name), val)
+						continue
+					}
+					raw, ok := decodeHeaderParameter(param, v)
+					if !ok {
+						return malformedParameter(param)
 					}
+					val, err := r.convertParameter(param.Schema, raw)
+					if err != nil {
+						return invalidParameter(param, err)
+					}
+					if err := r.validateParameter(param, val); err != nil {
+						return err
+					}
+					setParameter(c, param, val)
 
 				case "cookie":
-					v, err := c.Cookie(param.Name)
-					if err != nil && param.Required {
-						return ErrRequiredParameterMissing
+					raw, found, err := decodeCookieParameter(param, c.Cookies())
+					if err != nil {
+						return err
 					}
-					val := param.Schema.FromString(v.Value)
-					if val == nil {
-						return ErrRequiredParameterMissing
+					if !found {
+						if err := r.missingParameter(c, param); err != nil {
+							return err
+						}
+						continue
+					}
+					val, err := r.convertParameter(param.Schema, raw)
+					if err != nil {
+						return invalidParameter(param, err)
+					}
+					if err := r.validateParameter(param, val); err != nil {
+						return err
+					}
+					setParameter(c, param, val)
+
+				case "query":
+					// Parameters generated by WithQueryStruct are bound to the struct instead, after checking the schema keywords
+					structField := false
+					if r.QuerySchema != nil {
+						_, structField = r.QuerySchema.Properties[param.Name]
+					}
+
+					raw, found, err := decodeQueryParameter(param, c.QueryParams())
+					if err != nil {
+						return err
+					}
+					if !found {
+						if structField {
+							// Required fields are checked by the struct validation
+							continue
+						}
+						if err := r.missingParameter(c, param); err != nil {
+							return err
+						}
+						continue
+					}
+					val, err := r.convertParameter(param.Schema, raw)
+					if err != nil {
+						return invalidParameter(param, err)
+					}
+					if err := r.validateParameter(param, val); err != nil {
+						return err
+					}
+					if !structField {
+						setParameter(c, param, val)
+					}
+				}
+			}
+
+			// --------------------------------------------------------------------------------
+			// Bind path, header, and cookie structs
+			// --------------------------------------------------------------------------------
+			for _, in := range []v320.ParameterLocation{v320.PathParameter, v320.HeaderParameter, v320.CookieParameter} {
+				if s, ok := r.ParameterSchemas[in]; ok && s.SourceType != nil {
+					if err := bindParameterStruct(c, val, in, s); err != nil {
+						return err
 					}
-					c.Set(fmt.Sprintf("cookie.%s", param.Name), val)
 				}
 			}
 
@@ -123,6 +171,7 @@ func (r *RouteWrapper) middleware() echo.MiddlewareFunc {
 
 				// Create a new struct of the given type
 				v := reflect.New(r.QuerySchema.SourceType).Interface()
+				allocEmbedded(reflect.ValueOf(v).Elem())
 
 				// Bind the struct to the body
 				if err := (&echo.DefaultBinder{}).BindQueryParams(c, v); err != nil {
@@ -131,47 +180,102 @@ func (r *RouteWrapper) middleware() echo.MiddlewareFunc {
 
 				// Validate the bound struct
 				if err := val.StructCtx(c.Request().Context(), v); err != nil {
-					return err
+					return fmt.Errorf("%w: %w", ErrParameterInvalid, err)
 				}
 
 				// Add to context
 				c.Set("query", v)
+				GetRequestData(c).Query = v
+			}
+
+			// --------------------------------------------------------------------------------
+			// Check multipart size limits and part content types
+			// --------------------------------------------------------------------------------
+			if err := r.checkMultipart(c); err != nil {
+				return err
+			}
+
+			// --------------------------------------------------------------------------------
+			// Extract form from query
+			// --------------------------------------------------------------------------------
+			if r.FormSchema != nil && r.FormSchema.SourceType != nil && r.formFromQuery() {
+				// Form fields are declared as query parameters, which have already been converted
+				v, err := newParameterStruct(c, val, v320.QueryParameter, "form", r.FormSchema.SourceType)
+				if err != nil {
+					return err
+				}
+
+				// Add to context
+				c.Set("form", v)
+				GetRequestData(c).Form = v
 			}
 
 			// --------------------------------------------------------------------------------
 			// Extract request body
 			// --------------------------------------------------------------------------------
-			if len(r.RequestBodySchema) != 0 {
-				cts := c.Request().Header["Content-Type"]
-				mime := ""
-				if len(cts) == 1 {
-					mime = cts[0]
-					parts := strings.SplitN(mime, ";", 2)
-					if len(parts) > 0 {
-						mime = parts[0]
-					}
-					if schema, ok := r.RequestBodySchema[mime]; ok {
-						if schema.SourceType != nil {
-							// Create a new struct of the given type
-							v := reflect.New(schema.SourceType).Interface()
-
-							// Bind the struct to the body
-							if err := (&echo.DefaultBinder{}).BindBody(c, v); err != nil {
-								return err
-							}
-
-							// Validate the bound struct
-							if err := val.StructCtx(c.Request().Context(), v); err != nil {
-								return err
-							}
-
-							// Add to context
-							c.Set("body", v)
-						}
+			if declared := r.requestBodyMediaTypes(); len(declared) != 0 {
+				req := c.Request()
+
+				// Without a body there is nothing to bind
+				if !hasRequestBody(req) {
+					if r.requestBodyRequired() {
+						return ErrRequestBodyMissing
 					}
-				} else {
+					return next(c)
+				}
+
+				ct, err := requestContentType(req)
+				if err != nil {
+					return err
+				}
+				if ct == "" {
+					// Assume arbitrary binary data
+					ct = "application/octet-stream"
+				}
+
+				mime, ok := matchMediaType(declared, ct)
+				if !ok {
 					return ErrContentTypeNotSupported
 				}
+
+				if schema, ok := r.RequestBodySchema[mime]; ok {
+					if schema.SourceType != nil {
+						// Create a new struct of the given type
+						v := reflect.New(schema.SourceType).Interface()
+
+						// Bind the struct to the body
+						if err := r.API.bindBody(c, r.API.bodyDecoderMediaType(mime, ct), v); err != nil {
+							return err
+						}
+
+						// Validate the bound struct
+						if err := val.StructCtx(c.Request().Context(), v); err != nil {
+							return fmt.Errorf("%w: %w", ErrRequestBodyInvalid, err)
+						}
+
+						// Add to context
+						c.Set("body", v)
+						GetRequestData(c).Body = v
+					}
+				} else if r.FormSchema != nil && r.FormSchema.SourceType != nil {
+					// Create a new struct of the given type
+					v := reflect.New(r.FormSchema.SourceType).Interface()
+					allocEmbedded(reflect.ValueOf(v).Elem())
+
+					// Bind the struct to the body
+					if err := (&echo.DefaultBinder{}).BindBody(c, v); err != nil {
+						return err
+					}
+
+					// Validate the bound struct
+					if err := val.StructCtx(c.Request().Context(), v); err != nil {
+						return fmt.Errorf("%w: %w", ErrParameterInvalid, err)
+					}
+
+					// Add to context
+					c.Set("form", v)
+					GetRequestData(c).Form = v
+				}
 			}
 
 			return next(c)
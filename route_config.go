@@ -65,20 +65,31 @@ func WithOptionalSecurity() RouteConfigFunc {
 	}
 }
 
+// WithNoSecurity explicitly removes any security requirements declared at the top level of the spec from the route
+func WithNoSecurity() RouteConfigFunc {
+	return func(rw *RouteWrapper) *RouteWrapper {
+		rw.Operation.Security = v320.SecurityRequirements{}
+		return rw
+	}
+}
+
 // WithSecurityRequirement attaches a requirement to a route that the matching security scheme is fulfilled.
 // Attaches middleware that adds the security scheme value and scopes to the context at security.<name> and security.<name>.scopes
 func WithSecurityRequirement(name string, scopes []string) RouteConfigFunc {
+	return WithSecurityRequirementObject(&v320.SecurityRequirement{
+		name: scopes,
+	})
+}
+
+// WithSecurityRequirementObject attaches a requirement to a route that all of the listed security schemes are fulfilled together.
+// Each call adds an alternative requirement, only one of which must be met.
+func WithSecurityRequirementObject(req *v320.SecurityRequirement) RouteConfigFunc {
 	return func(rw *RouteWrapper) *RouteWrapper {
-		// Lookup the matching scheme
-		scheme := rw.API.Spec.GetComponents().GetSecurityScheme(name)
-		if scheme == nil {
-			panic("echopen: security scheme not registered")
-		}
+		// Check the schemes are registered and declare the required scopes
+		rw.API.validateSecurityRequirement(req)
 
 		// Add the requirement to the operation definition
-		rw.Operation.AddSecurityRequirement(&v320.SecurityRequirement{
-			name: scopes,
-		})
+		rw.Operation.AddSecurityRequirement(req)
 
 		return rw
 	}
@@ -0,0 +1,148 @@
+package echopen_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaFromString(t *testing.T) {
+	str := func(f v320.SchemaFormat) *v320.Schema {
+		return &v320.Schema{Type: v320.StringSchemaType, Format: f}
+	}
+
+	type tcd struct {
+		Name     string
+		Schema   *v320.Schema
+		Value    string
+		Expected interface{}
+	}
+
+	defs := []tcd{
+		{"nil", nil, "foo", "foo"},
+		{"untyped", &v320.Schema{}, "foo", "foo"},
+		{"string", str(""), "foo", "foo"},
+		{"boolean", &v320.Schema{Type: v320.BooleanSchemaType}, "true", true},
+		{"integer", &v320.Schema{Type: v320.IntegerSchemaType}, "-3", -3},
+		{"int8", &v320.Schema{Type: v320.IntegerSchemaType, Format: "int8"}, "-3", int8(-3)},
+		{"int64", &v320.Schema{Type: v320.IntegerSchemaType, Format: "int64"}, "-3", int64(-3)},
+		{"uint64", &v320.Schema{Type: v320.IntegerSchemaType, Format: "uint64"}, "3", uint64(3)},
+		{"char", &v320.Schema{Type: v320.IntegerSchemaType, Format: "char"}, "3", uint8(3)},
+		{"float", &v320.Schema{Type: v320.NumberSchemaType, Format: "float"}, "1.5", float32(1.5)},
+		{"double", &v320.Schema{Type: v320.NumberSchemaType, Format: "double"}, "1.5", 1.5},
+		{"date-time", str("date-time"), "2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"date", str("date"), "2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"time", str("time"), "03:04:05", time.Date(0, 1, 1, 3, 4, 5, 0, time.UTC)},
+		{"time_offset", str("time"), "03:04:05Z", time.Date(0, 1, 1, 3, 4, 5, 0, time.UTC)},
+		{"duration", str("duration"), "P1DT2H30M", 26*time.Hour + 30*time.Minute},
+		{"duration_fraction", str("duration"), "PT0.5S", 500 * time.Millisecond},
+		{"duration_go", str("duration"), "1h30m", 90 * time.Minute},
+		{"byte", str("byte"), "aGVsbG8=", []byte("hello")},
+		{"email", str("email"), "user@example.com", "user@example.com"},
+		{"uri", str("uri"), "https://example.com/a?b=c", "https://example.com/a?b=c"},
+		{"ipv4", str("ipv4"), "10.0.0.1", "10.0.0.1"},
+		{"ipv6", str("ipv6"), "::1", "::1"},
+		{"uuid", str("uuid"), "11c7810d-6627-497a-91e9-e3dc4812ce30", uuid.FromStringOrNil("11c7810d-6627-497a-91e9-e3dc4812ce30")},
+		{"array", &v320.Schema{Type: v320.ArraySchemaType, Items: v320.NewSchemaValue(&v320.Schema{Type: v320.IntegerSchemaType})}, "1,2,3", []interface{}{1, 2, 3}},
+		{"array_empty", &v320.Schema{Type: v320.ArraySchemaType}, "", []interface{}{}},
+		{"null", &v320.Schema{Type: v320.NullSchemaType}, "null", nil},
+	}
+
+	for _, d := range defs {
+		t.Run(d.Name, func(t *testing.T) {
+			v, err := d.Schema.FromString(d.Value)
+			assert.NoError(t, err)
+			assert.Equal(t, d.Expected, v)
+		})
+	}
+
+	invalid := []tcd{
+		{"boolean", &v320.Schema{Type: v320.BooleanSchemaType}, "yes", nil},
+		{"integer", &v320.Schema{Type: v320.IntegerSchemaType}, "1.5", nil},
+		{"int8_range", &v320.Schema{Type: v320.IntegerSchemaType, Format: "int8"}, "300", nil},
+		{"uint_negative", &v320.Schema{Type: v320.IntegerSchemaType, Format: "uint32"}, "-1", nil},
+		{"number", &v320.Schema{Type: v320.NumberSchemaType}, "abc", nil},
+		{"date", str("date"), "2024-13-01", nil},
+		{"duration", str("duration"), "P1Y", nil},
+		{"byte", str("byte"), "not base64!", nil},
+		{"email", str("email"), "User <user@example.com>", nil},
+		{"uri", str("uri"), "/relative", nil},
+		{"ipv4", str("ipv4"), "::1", nil},
+		{"ipv6", str("ipv6"), "10.0.0.1", nil},
+		{"uuid", str("uuid"), "foo", nil},
+		{"array_item", &v320.Schema{Type: v320.ArraySchemaType, Items: v320.NewSchemaValue(&v320.Schema{Type: v320.IntegerSchemaType})}, "1,a", nil},
+		{"object", &v320.Schema{Type: v320.ObjectSchemaType}, "a,b", nil},
+	}
+
+	for _, d := range invalid {
+		t.Run("invalid_"+d.Name, func(t *testing.T) {
+			v, err := d.Schema.FromString(d.Value)
+			assert.Error(t, err)
+			assert.Nil(t, v)
+		})
+	}
+}
+
+func TestSchemaFormatConverter(t *testing.T) {
+	upper := func(val string) (interface{}, error) {
+		if val == "" {
+			return nil, errors.New("empty")
+		}
+		return strings.ToUpper(val), nil
+	}
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, fmt.Sprint(c.Get("query.name")))
+	}
+	param := echopen.WithQueryParameterConfig(&echopen.QueryParameterConfig{
+		Name:   "name",
+		Schema: &v320.Schema{Type: v320.StringSchemaType, Format: "upper"},
+	})
+
+	api := echopen.New("Test", "1.0.0", echopen.WithFormatConverter("upper", upper))
+	api.GET("/", handler, param)
+
+	// Converters are registered per API
+	other := echopen.New("Other", "1.0.0")
+	other.GET("/", handler, param)
+
+	_, res := executeRequest(api, http.MethodGet, "/?name=foo", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "FOO", res.Body.String())
+
+	_, res = executeRequest(api, http.MethodGet, "/?name=", nil)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	_, res = executeRequest(other, http.MethodGet, "/?name=foo", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "foo", res.Body.String())
+}
+
+func TestRouteParameterConversionError(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			active, err := echopen.QueryParam[bool](c, "active")
+			assert.NoError(t, err)
+			assert.True(t, active)
+			return c.NoContent(204)
+		},
+		echopen.WithQueryParameter("active", "Active", false),
+	)
+
+	_, res := executeRequest(api, http.MethodGet, "/?active=true", nil)
+	assert.Equal(t, 204, res.Result().StatusCode)
+
+	_, res = executeRequest(api, http.MethodGet, "/?active=maybe", nil)
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
@@ -0,0 +1,392 @@
+package echopen
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+)
+
+// Credential is the raw credential extracted from a request for a security scheme
+type Credential struct {
+	// API key, bearer token, basic auth username, or client certificate subject
+	Value string
+	// Basic auth password
+	Password string
+	// Verified client certificate for mutualTLS schemes
+	Certificate *x509.Certificate
+}
+
+// SecurityValidator validates the credential supplied for a security scheme.
+// Returns the authenticated principal and the scopes granted to it, or an error if the credential is not valid.
+type SecurityValidator interface {
+	Validate(c echo.Context, cred *Credential) (principal interface{}, scopes []string, err error)
+}
+
+// SecurityValidatorFunc is an adapter to allow the use of ordinary functions as a SecurityValidator
+type SecurityValidatorFunc func(c echo.Context, cred *Credential) (interface{}, []string, error)
+
+func (f SecurityValidatorFunc) Validate(c echo.Context, cred *Credential) (interface{}, []string, error) {
+	return f(c, cred)
+}
+
+// BearerVerifierFunc verifies a bearer token for an http bearer scheme, returning the authenticated principal and its granted scopes
+type BearerVerifierFunc func(c echo.Context, token string) (interface{}, []string, error)
+
+func (f BearerVerifierFunc) Validate(c echo.Context, cred *Credential) (interface{}, []string, error) {
+	return f(c, cred.Value)
+}
+
+// BasicVerifierFunc verifies a username and password for an http basic scheme, returning the authenticated principal and its granted scopes
+type BasicVerifierFunc func(c echo.Context, username string, password string) (interface{}, []string, error)
+
+func (f BasicVerifierFunc) Validate(c echo.Context, cred *Credential) (interface{}, []string, error) {
+	return f(c, cred.Value, cred.Password)
+}
+
+// SecurityError is returned when security requirements are not met.
+// Challenges are added to the response as WWW-Authenticate headers by the default error handler.
+type SecurityError struct {
+	Err        error
+	Challenges []string
+}
+
+func (e *SecurityError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SecurityError) Unwrap() error {
+	return e.Err
+}
+
+// Authentication describes a security scheme satisfied by the request
+type Authentication struct {
+	// Name of the satisfied security scheme
+	Scheme string
+	// Type of the satisfied security scheme
+	Type v320.SecuritySchemeType
+	// Raw credential extracted from the request
+	Credential *Credential
+	// Principal returned by the registered validator, nil if no validator is registered
+	Principal interface{}
+	// Scopes required by the security requirement
+	Scopes []string
+	// Scopes granted by the registered validator
+	GrantedScopes []string
+}
+
+// Principal returns the principal of the first satisfied scheme whose validator returned a value of type T
+func Principal[T any](c echo.Context) (T, bool) {
+	auths, _ := c.Get("security").([]*Authentication)
+	for _, auth := range auths {
+		if p, ok := auth.Principal.(T); ok {
+			return p, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// SecurityValue returns the authentication for the named scheme, if it was satisfied by the request
+func SecurityValue(c echo.Context, scheme string) (*Authentication, bool) {
+	auths, _ := c.Get("security").([]*Authentication)
+	for _, auth := range auths {
+		if auth.Scheme == scheme {
+			return auth, true
+		}
+	}
+	return nil, false
+}
+
+// securityRequirements returns the requirements that apply to the operation.
+// Operations without their own requirements inherit those declared at the top level of the spec.
+func (r *RouteWrapper) securityRequirements() v320.SecurityRequirements {
+	if r.Operation.Security != nil {
+		return r.Operation.Security
+	}
+	return r.API.Spec.Security
+}
+
+// checkSecurity verifies the operation security requirements against the request.
+// Requirements are alternatives, the first for which every listed scheme is satisfied is used.
+// The Authentication for each matched scheme is added to the context for access via Principal and SecurityValue.
+// Values and scopes for matched schemes are also added to the context at security.<name> and security.<name>.scopes,
+// with the principal returned by any registered validator at security.<name>.principal
+func (r *RouteWrapper) checkSecurity(c echo.Context) error {
+	security := r.securityRequirements()
+	if len(security) == 0 {
+		return nil
+	}
+
+	optional := false
+	insufficientScope := false
+	challenges := []string{}
+
+	for _, req := range security {
+		if len(*req) == 0 {
+			// Empty object makes all requirements optional
+			optional = true
+			continue
+		}
+
+		auths, ch, err := r.checkSecurityRequirement(c, *req)
+		challenges = append(challenges, ch...)
+		if errors.Is(err, ErrRequestTooLarge) {
+			// The body has been consumed, so no other requirement can be checked
+			return err
+		}
+		if errors.Is(err, ErrInsufficientScope) {
+			insufficientScope = true
+		}
+		if err != nil {
+			continue
+		}
+
+		for _, auth := range auths {
+			c.Set(fmt.Sprintf("security.%s", auth.Scheme), auth.Credential.Value)
+			c.Set(fmt.Sprintf("security.%s.scopes", auth.Scheme), auth.Scopes)
+			c.Set(fmt.Sprintf("security.%s.principal", auth.Scheme), auth.Principal)
+		}
+		c.Set("security", auths)
+		return nil
+	}
+
+	if optional {
+		return nil
+	} else if insufficientScope {
+		return &SecurityError{Err: ErrInsufficientScope, Challenges: challenges}
+	}
+	return &SecurityError{Err: ErrSecurityRequirementsNotMet, Challenges: challenges}
+}
+
+// checkSecurityRequirement checks every scheme within a single requirement is satisfied.
+// Returns ErrInsufficientScope if all schemes were authenticated but at least one lacked the required scopes.
+func (r *RouteWrapper) checkSecurityRequirement(c echo.Context, req v320.SecurityRequirement) ([]*Authentication, []string, error) {
+	// Evaluate schemes in a stable order
+	names := make([]string, 0, len(req))
+	for name := range req {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	auths := []*Authentication{}
+	challenges := []string{}
+	var reqErr error
+
+	for _, name := range names {
+		scopes := req[name]
+
+		auth, ch, err := r.checkSecurityScheme(c, name, scopes)
+		challenges = append(challenges, ch...)
+		if errors.Is(err, ErrRequestTooLarge) {
+			return nil, challenges, err
+		}
+		if err != nil {
+			if reqErr == nil || errors.Is(err, ErrSecurityRequirementsNotMet) {
+				reqErr = err
+			}
+			continue
+		}
+
+		auths = append(auths, auth)
+	}
+
+	if reqErr != nil {
+		return nil, challenges, reqErr
+	}
+	return auths, challenges, nil
+}
+
+// checkSecurityScheme extracts and validates the credential for a single named scheme
+func (r *RouteWrapper) checkSecurityScheme(c echo.Context, name string, scopes []string) (*Authentication, []string, error) {
+	scheme := r.API.Spec.GetComponents().GetSecurityScheme(name)
+	if scheme == nil {
+		// Scheme existence is checked at the point the requirement is added
+		return nil, nil, ErrSecurityRequirementsNotMet
+	}
+
+	cred, ok := extractCredential(c, scheme)
+	if !ok {
+		return nil, r.appendChallenge(nil, scheme, ""), ErrSecurityRequirementsNotMet
+	}
+
+	// Without a validator the presence of the credential is sufficient, except for http, oauth2 and openIdConnect credentials,
+	// and scopes can never be granted
+	auth := &Authentication{Scheme: name, Type: scheme.Type, Credential: cred, Scopes: scopes}
+	v, ok := r.API.securityValidators[name]
+	if !ok && requiresValidator(scheme) {
+		return nil, r.appendChallenge(nil, scheme, `error="invalid_token"`), ErrSecurityRequirementsNotMet
+	}
+	if !ok && len(scopes) > 0 {
+		ch := fmt.Sprintf(`error="insufficient_scope", scope=%q`, strings.Join(scopes, " "))
+		return nil, r.appendChallenge(nil, scheme, ch), ErrInsufficientScope
+	}
+	if ok {
+		p, granted, err := v.Validate(c, cred)
+		if errors.Is(err, ErrRequestTooLarge) {
+			// The request cannot be verified, rather than being unauthorized
+			return nil, nil, err
+		}
+		if err != nil {
+			return nil, r.appendChallenge(nil, scheme, `error="invalid_token"`), ErrSecurityRequirementsNotMet
+		}
+		if !hasScopes(granted, scopes) {
+			ch := fmt.Sprintf(`error="insufficient_scope", scope=%q`, strings.Join(scopes, " "))
+			return nil, r.appendChallenge(nil, scheme, ch), ErrInsufficientScope
+		}
+		auth.Principal = p
+		auth.GrantedScopes = granted
+	}
+
+	return auth, nil, nil
+}
+
+// checkSecurityValidators panics if the route has a security requirement which the registered validators cannot enforce
+func (r *RouteWrapper) checkSecurityValidators() {
+	for _, req := range r.securityRequirements() {
+		for name, scopes := range *req {
+			if _, ok := r.API.securityValidators[name]; ok {
+				continue
+			}
+			if requiresValidator(r.API.Spec.GetComponents().GetSecurityScheme(name)) {
+				panic(fmt.Sprintf("echopen: security scheme '%s' requires a validator to verify credentials", name))
+			}
+			if len(scopes) > 0 {
+				panic(fmt.Sprintf("echopen: security requirement for scheme '%s' lists scopes but no validator is registered", name))
+			}
+		}
+	}
+}
+
+// requiresValidator checks if the scheme carries tokens or passwords which must be verified rather than just be present
+func requiresValidator(scheme *v320.SecurityScheme) bool {
+	if scheme == nil {
+		return false
+	}
+	switch scheme.Type {
+	case v320.HTTPSecuritySchemeType, v320.OAuth2SecuritySchemeType, v320.OpenIDConnectSecuritySchemeType:
+		return true
+	}
+	return false
+}
+
+// appendChallenge adds a WWW-Authenticate challenge for schemes using the Authorization header, using the spec title as the realm
+func (r *RouteWrapper) appendChallenge(challenges []string, scheme *v320.SecurityScheme, params string) []string {
+	authScheme := ""
+	switch scheme.Type {
+	case v320.HTTPSecuritySchemeType:
+		authScheme = strings.ToLower(scheme.Scheme)
+	case v320.OAuth2SecuritySchemeType, v320.OpenIDConnectSecuritySchemeType:
+		authScheme = "bearer"
+	}
+
+	var ch string
+	switch authScheme {
+	case "bearer":
+		ch = fmt.Sprintf("Bearer realm=%q", r.API.Spec.Info.Title)
+		if params != "" {
+			ch += ", " + params
+		}
+	case "basic":
+		ch = fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, r.API.Spec.Info.Title)
+	default:
+		return challenges
+	}
+
+	return append(challenges, ch)
+}
+
+// hasScopes checks all required scopes are present in the granted scopes
+func hasScopes(granted []string, required []string) bool {
+	for _, req := range required {
+		found := false
+		for _, g := range granted {
+			if g == req {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// extractCredential reads the credential for a security scheme from the request
+func extractCredential(c echo.Context, scheme *v320.SecurityScheme) (*Credential, bool) {
+	switch scheme.Type {
+	case v320.APIKeySecuritySchemeType:
+		if val, ok := extractAPIKey(c, scheme); ok {
+			return &Credential{Value: val}, true
+		}
+	case v320.MutualTLSSecuritySchemeType:
+		if cert, ok := extractClientCertificate(c); ok {
+			return &Credential{Value: cert.Subject.String(), Certificate: cert}, true
+		}
+	case v320.OAuth2SecuritySchemeType, v320.OpenIDConnectSecuritySchemeType:
+		if token, ok := extractBearerToken(c); ok {
+			return &Credential{Value: token}, true
+		}
+	case v320.HTTPSecuritySchemeType:
+		switch strings.ToLower(scheme.Scheme) {
+		case "bearer":
+			if token, ok := extractBearerToken(c); ok {
+				return &Credential{Value: token}, true
+			}
+		case "basic":
+			if username, password, ok := c.Request().BasicAuth(); ok {
+				return &Credential{Value: username, Password: password}, true
+			}
+		}
+	}
+
+	// Unsupported scheme types can never be satisfied
+	return nil, false
+}
+
+// extractAPIKey reads the API key for the scheme from the header, query string, or cookie named by the scheme
+func extractAPIKey(c echo.Context, scheme *v320.SecurityScheme) (string, bool) {
+	switch scheme.In {
+	case "header":
+		val := c.Request().Header.Get(scheme.Name)
+		return val, val != ""
+	case "query":
+		val := c.QueryParam(scheme.Name)
+		return val, val != ""
+	case "cookie":
+		cookie, err := c.Cookie(scheme.Name)
+		if err != nil {
+			return "", false
+		}
+		return cookie.Value, cookie.Value != ""
+	default:
+		// Unknown locations cannot supply a key
+		return "", false
+	}
+}
+
+// extractBearerToken reads a bearer token from the Authorization header
+func extractBearerToken(c echo.Context) (string, bool) {
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	parts := strings.SplitN(auth, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", false
+	}
+	token := strings.TrimSpace(parts[1])
+	return token, token != ""
+}
+
+// extractClientCertificate returns the leaf client certificate if it was verified during the TLS handshake
+func extractClientCertificate(c echo.Context) (*x509.Certificate, bool) {
+	state := c.Request().TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return state.VerifiedChains[0][0], true
+}
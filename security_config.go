@@ -0,0 +1,203 @@
+package echopen
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+)
+
+// AddSecurityScheme validates and registers a security scheme under the spec components.
+// Panics if required fields for the scheme type are missing or invalid.
+func (a *APIWrapper) AddSecurityScheme(name string, s *v320.SecurityScheme) {
+	if err := validateSecurityScheme(s); err != nil {
+		panic(fmt.Sprintf("echopen: invalid security scheme '%s': %s", name, err))
+	}
+	a.Spec.GetComponents().AddSecurityScheme(name, s)
+}
+
+// WithSecurityScheme validates and registers a security scheme
+func WithSecurityScheme(name string, s *v320.SecurityScheme) WrapperConfigFunc {
+	return func(a *APIWrapper) *APIWrapper {
+		a.AddSecurityScheme(name, s)
+		return a
+	}
+}
+
+// WithAPIKeyScheme registers an apiKey scheme read from the named header, query parameter, or cookie
+func WithAPIKeyScheme(name string, in string, paramName string) WrapperConfigFunc {
+	return WithSecurityScheme(name, &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   in,
+		Name: paramName,
+	})
+}
+
+// WithBearerScheme registers an http bearer scheme, bearerFormat is an optional hint such as "JWT"
+func WithBearerScheme(name string, bearerFormat string) WrapperConfigFunc {
+	return WithSecurityScheme(name, &v320.SecurityScheme{
+		Type:         v320.HTTPSecuritySchemeType,
+		Scheme:       "bearer",
+		BearerFormat: bearerFormat,
+	})
+}
+
+// WithBasicScheme registers an http basic scheme
+func WithBasicScheme(name string) WrapperConfigFunc {
+	return WithSecurityScheme(name, &v320.SecurityScheme{
+		Type:   v320.HTTPSecuritySchemeType,
+		Scheme: "basic",
+	})
+}
+
+// WithOAuth2Scheme registers an oauth2 scheme, the flows must declare all scopes used by security requirements
+func WithOAuth2Scheme(name string, flows *v320.OAuthFlows) WrapperConfigFunc {
+	return WithSecurityScheme(name, &v320.SecurityScheme{
+		Type:  v320.OAuth2SecuritySchemeType,
+		Flows: flows,
+	})
+}
+
+// WithOpenIDConnectScheme registers an openIdConnect scheme using the given discovery URL
+func WithOpenIDConnectScheme(name string, openIDConnectURL string) WrapperConfigFunc {
+	return WithSecurityScheme(name, &v320.SecurityScheme{
+		Type:             v320.OpenIDConnectSecuritySchemeType,
+		OpenIDConnectURL: openIDConnectURL,
+	})
+}
+
+// WithMutualTLSScheme registers a mutualTLS scheme
+func WithMutualTLSScheme(name string) WrapperConfigFunc {
+	return WithSecurityScheme(name, &v320.SecurityScheme{
+		Type: v320.MutualTLSSecuritySchemeType,
+	})
+}
+
+// validateSecurityRequirement checks every scheme in the requirement is registered,
+// and that scopes required of oauth2 schemes are declared by at least one of the scheme flows
+func (a *APIWrapper) validateSecurityRequirement(req *v320.SecurityRequirement) {
+	for name, scopes := range *req {
+		scheme := a.Spec.GetComponents().GetSecurityScheme(name)
+		if scheme == nil {
+			panic("echopen: security scheme not registered")
+		}
+
+		if scheme.Type != v320.OAuth2SecuritySchemeType || scheme.Flows == nil {
+			continue
+		}
+
+		for _, scope := range scopes {
+			declared := false
+			for _, flow := range oauthFlows(scheme.Flows) {
+				if flow == nil {
+					continue
+				}
+				if _, ok := flow.Scopes[scope]; ok {
+					declared = true
+				}
+			}
+			if !declared {
+				panic(fmt.Sprintf("echopen: scope '%s' not declared by security scheme '%s'", scope, name))
+			}
+		}
+	}
+}
+
+// oauthFlows returns the flows of an oauth2 scheme in a fixed order, undeclared flows are nil
+func oauthFlows(f *v320.OAuthFlows) []*v320.OAuthFlow {
+	return []*v320.OAuthFlow{f.Implicit, f.Password, f.ClientCredentials, f.AuthorizationCode}
+}
+
+func validateSecurityScheme(s *v320.SecurityScheme) error {
+	switch s.Type {
+	case v320.APIKeySecuritySchemeType:
+		if s.Name == "" {
+			return fmt.Errorf("apiKey scheme requires a name")
+		}
+		switch s.In {
+		case "header", "query", "cookie":
+		default:
+			return fmt.Errorf("apiKey scheme location must be header, query, or cookie, got '%s'", s.In)
+		}
+
+	case v320.HTTPSecuritySchemeType:
+		if s.Scheme == "" {
+			return fmt.Errorf("http scheme requires a scheme name")
+		}
+		if s.BearerFormat != "" && !strings.EqualFold(s.Scheme, "bearer") {
+			return fmt.Errorf("bearerFormat only applies to bearer schemes")
+		}
+
+	case v320.OAuth2SecuritySchemeType:
+		if s.Flows == nil {
+			return fmt.Errorf("oauth2 scheme requires flows")
+		}
+		names := []string{"implicit", "password", "clientCredentials", "authorizationCode"}
+		count := 0
+		for i, flow := range oauthFlows(s.Flows) {
+			if flow == nil {
+				continue
+			}
+			count++
+			if err := validateOAuthFlow(names[i], flow); err != nil {
+				return err
+			}
+		}
+		if count == 0 {
+			return fmt.Errorf("oauth2 scheme requires at least one flow")
+		}
+
+	case v320.OpenIDConnectSecuritySchemeType:
+		if err := validateSchemeURL("openIdConnectUrl", s.OpenIDConnectURL); err != nil {
+			return err
+		}
+
+	case v320.MutualTLSSecuritySchemeType:
+
+	default:
+		return fmt.Errorf("unknown scheme type '%s'", s.Type)
+	}
+
+	return nil
+}
+
+func validateOAuthFlow(name string, f *v320.OAuthFlow) error {
+	if name == "implicit" || name == "authorizationCode" {
+		if err := validateSchemeURL(name+" authorizationUrl", f.AuthorizationURL); err != nil {
+			return err
+		}
+	}
+	if name != "implicit" {
+		if err := validateSchemeURL(name+" tokenUrl", f.TokenURL); err != nil {
+			return err
+		}
+	}
+	if f.RefreshURL != "" {
+		if err := validateSchemeURL(name+" refreshUrl", f.RefreshURL); err != nil {
+			return err
+		}
+	}
+
+	for scope := range f.Scopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\n") {
+			return fmt.Errorf("%s scope '%s' is not a valid scope name", name, scope)
+		}
+	}
+
+	return nil
+}
+
+func validateSchemeURL(field string, raw string) error {
+	if raw == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid URL: %w", field, err)
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s must use http or https", field)
+	}
+	return nil
+}
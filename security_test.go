@@ -0,0 +1,642 @@
+package echopen_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anteo/echopen/v2"
+	v320 "github.com/anteo/echopen/v2/openapi/v3.2.0"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecurityAPIKeyLocations(t *testing.T) {
+	type tcd struct {
+		Name     string
+		In       string
+		Request  func(req *http.Request)
+		Expected int
+	}
+
+	defs := []tcd{
+		{"header", "header", func(req *http.Request) { req.Header.Set("X-API-Key", "secret") }, http.StatusOK},
+		{"header_missing", "header", func(req *http.Request) {}, http.StatusUnauthorized},
+		{"query", "query", func(req *http.Request) { req.URL.RawQuery = "X-API-Key=secret" }, http.StatusOK},
+		{"query_missing", "query", func(req *http.Request) {}, http.StatusUnauthorized},
+		{"cookie", "cookie", func(req *http.Request) { req.AddCookie(&http.Cookie{Name: "X-API-Key", Value: "secret"}) }, http.StatusOK},
+		{"cookie_missing", "cookie", func(req *http.Request) {}, http.StatusUnauthorized},
+		{"unknown_location", "body", func(req *http.Request) { req.Header.Set("X-API-Key", "secret") }, http.StatusUnauthorized},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			api := echopen.New(
+				"Test",
+				"1.0.0",
+				echopen.WithSecurityValidator("api_key", echopen.SecurityValidatorFunc(
+					func(c echo.Context, cred *echopen.Credential) (interface{}, []string, error) {
+						return cred.Value, []string{"read"}, nil
+					},
+				)),
+			)
+			api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
+				Type: v320.APIKeySecuritySchemeType,
+				In:   tc.In,
+				Name: "X-API-Key",
+			})
+			api.GET(
+				"/",
+				func(c echo.Context) error {
+					assert.Equal(t, "secret", c.Get("security.api_key"))
+					assert.Equal(t, []string{"read"}, c.Get("security.api_key.scopes"))
+					return c.NoContent(http.StatusOK)
+				},
+				echopen.WithSecurityRequirement("api_key", []string{"read"}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tc.Request(req)
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+}
+
+func TestSecurityUnknownSchemeType(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.Spec.GetComponents().AddSecurityScheme("unknown", &v320.SecurityScheme{
+		Type: "unknown",
+	})
+	api.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("unknown", []string{}))
+
+	_, res := executeRequest(api, http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestSecurityHTTPBearer(t *testing.T) {
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithBearerVerifier("bearer", func(c echo.Context, token string) (interface{}, []string, error) {
+			if token != "valid" {
+				return nil, nil, fmt.Errorf("invalid token")
+			}
+			return "user-1", []string{"read"}, nil
+		}),
+	)
+	api.Spec.GetComponents().AddSecurityScheme("bearer", &v320.SecurityScheme{
+		Type:         v320.HTTPSecuritySchemeType,
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	})
+	api.GET("/", func(c echo.Context) error {
+		assert.Equal(t, "valid", c.Get("security.bearer"))
+		assert.Equal(t, "user-1", c.Get("security.bearer.principal"))
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("bearer", []string{}))
+	api.GET("/read", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("bearer", []string{"read"}))
+	api.GET("/write", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("bearer", []string{"write"}))
+
+	for target, code := range map[string]int{"/read": http.StatusOK, "/write": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Authorization", "Bearer valid")
+		res := httptest.NewRecorder()
+		api.Engine.ServeHTTP(res, req)
+		assert.Equal(t, code, res.Code, target)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Equal(t, `Bearer realm="Test", error="invalid_token"`, res.Header().Get("WWW-Authenticate"))
+
+	_, res = executeRequest(api, http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Equal(t, `Bearer realm="Test"`, res.Header().Get("WWW-Authenticate"))
+}
+
+func TestSecurityHTTPBasic(t *testing.T) {
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithBasicVerifier("basic", func(c echo.Context, username string, password string) (interface{}, []string, error) {
+			if username != "admin" || password != "hunter2" {
+				return nil, nil, fmt.Errorf("invalid credentials")
+			}
+			return "admin-principal", nil, nil
+		}),
+	)
+	api.Spec.GetComponents().AddSecurityScheme("basic", &v320.SecurityScheme{
+		Type:   v320.HTTPSecuritySchemeType,
+		Scheme: "basic",
+	})
+	api.GET("/", func(c echo.Context) error {
+		assert.Equal(t, "admin", c.Get("security.basic"))
+		assert.Equal(t, "admin-principal", c.Get("security.basic.principal"))
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("basic", []string{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("admin", "wrong")
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Equal(t, `Basic realm="Test", charset="UTF-8"`, res.Header().Get("WWW-Authenticate"))
+}
+
+func TestSecurityValidatorScopes(t *testing.T) {
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithSecurityValidator("api_key", echopen.SecurityValidatorFunc(func(c echo.Context, cred *echopen.Credential) (interface{}, []string, error) {
+			switch cred.Value {
+			case "reader":
+				return "reader", []string{"read"}, nil
+			case "admin":
+				return "admin", []string{"read", "write"}, nil
+			}
+			return nil, nil, fmt.Errorf("unknown key")
+		})),
+	)
+	api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   "header",
+		Name: "X-API-Key",
+	})
+	api.POST("/", func(c echo.Context) error {
+		assert.Equal(t, "admin", c.Get("security.api_key.principal"))
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("api_key", []string{"read", "write"}))
+
+	type tcd struct {
+		Name     string
+		Key      string
+		Expected int
+	}
+
+	defs := []tcd{
+		{"granted", "admin", http.StatusOK},
+		{"insufficient_scope", "reader", http.StatusForbidden},
+		{"invalid", "unknown", http.StatusUnauthorized},
+		{"missing", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.Key != "" {
+				req.Header.Set("X-API-Key", tc.Key)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+}
+
+func TestSecurityRequirementSemantics(t *testing.T) {
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithBearerVerifier("bearer", func(c echo.Context, token string) (interface{}, []string, error) {
+			if token != "valid" {
+				return nil, nil, fmt.Errorf("invalid token")
+			}
+			return "user-1", []string{"read"}, nil
+		}),
+	)
+	api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   "header",
+		Name: "X-API-Key",
+	})
+	api.Spec.GetComponents().AddSecurityScheme("client_id", &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   "query",
+		Name: "client_id",
+	})
+	api.Spec.GetComponents().AddSecurityScheme("bearer", &v320.SecurityScheme{
+		Type:   v320.HTTPSecuritySchemeType,
+		Scheme: "bearer",
+	})
+
+	// (api_key AND bearer) OR client_id
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		},
+		echopen.WithSecurityRequirementObject(&v320.SecurityRequirement{
+			"api_key": {},
+			"bearer":  {},
+		}),
+		echopen.WithSecurityRequirement("client_id", []string{}),
+	)
+
+	type tcd struct {
+		Name     string
+		Target   string
+		APIKey   string
+		Bearer   string
+		Expected int
+	}
+
+	defs := []tcd{
+		{"all_of_first", "/", "key", "valid", http.StatusOK},
+		{"api_key_only", "/", "key", "", http.StatusUnauthorized},
+		{"bearer_only", "/", "", "valid", http.StatusUnauthorized},
+		{"api_key_invalid_bearer", "/", "key", "invalid", http.StatusUnauthorized},
+		{"second_alternative", "/?client_id=abc", "", "", http.StatusOK},
+		{"none", "/", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.Target, nil)
+			if tc.APIKey != "" {
+				req.Header.Set("X-API-Key", tc.APIKey)
+			}
+			if tc.Bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Bearer)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+}
+
+func TestSecurityGroupRequirementAllOf(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   "header",
+		Name: "X-API-Key",
+	})
+	api.Spec.GetComponents().AddSecurityScheme("client_id", &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   "query",
+		Name: "client_id",
+	})
+
+	api.Group("/group", echopen.WithGroupSecurityRequirement(&v320.SecurityRequirement{
+		"api_key":   {},
+		"client_id": {},
+	})).GET("/test", func(c echo.Context) error {
+		assert.Equal(t, "key", c.Get("security.api_key"))
+		assert.Equal(t, "abc", c.Get("security.client_id"))
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/group/test", nil)
+	req.Header.Set("X-API-Key", "key")
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/group/test?client_id=abc", nil)
+	req.Header.Set("X-API-Key", "key")
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestSecuritySpecRequirement(t *testing.T) {
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		func(a *echopen.APIWrapper) *echopen.APIWrapper {
+			a.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
+				Type: v320.APIKeySecuritySchemeType,
+				In:   "header",
+				Name: "X-API-Key",
+			})
+			a.Spec.GetComponents().AddSecurityScheme("client_id", &v320.SecurityScheme{
+				Type: v320.APIKeySecuritySchemeType,
+				In:   "query",
+				Name: "client_id",
+			})
+			return a
+		},
+		echopen.WithSpecSecurityRequirement("api_key", []string{}),
+	)
+
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	api.GET("/inherited", handler)
+	api.GET("/public", handler, echopen.WithNoSecurity())
+	api.GET("/override", handler, echopen.WithSecurityRequirement("client_id", []string{}))
+
+	type tcd struct {
+		Name     string
+		Target   string
+		APIKey   string
+		Expected int
+	}
+
+	defs := []tcd{
+		{"inherited_missing", "/inherited", "", http.StatusUnauthorized},
+		{"inherited", "/inherited", "key", http.StatusOK},
+		{"public", "/public", "", http.StatusOK},
+		{"override_global_only", "/override", "key", http.StatusUnauthorized},
+		{"override", "/override?client_id=abc", "", http.StatusOK},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.Target, nil)
+			if tc.APIKey != "" {
+				req.Header.Set("X-API-Key", tc.APIKey)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+
+	buf, err := json.Marshal(api.Spec)
+	assert.NoError(t, err)
+	assert.Contains(t, string(buf), `"operationId":"getPublic","security":[]`)
+	assert.NotContains(t, string(buf), `"operationId":"getInherited","security"`)
+
+	buf, err = yaml.Marshal(api.Spec.Copy())
+	assert.NoError(t, err)
+	assert.Contains(t, string(buf), "security: []")
+}
+
+func TestSecurityMutualTLS(t *testing.T) {
+	api := echopen.New("Test", "1.0.0")
+	api.Spec.GetComponents().AddSecurityScheme("mtls", &v320.SecurityScheme{
+		Type: v320.MutualTLSSecuritySchemeType,
+	})
+	api.GET("/", func(c echo.Context) error {
+		assert.Equal(t, "CN=client.internal,O=Mesh", c.Get("security.mtls"))
+		return c.NoContent(http.StatusOK)
+	}, echopen.WithSecurityRequirement("mtls", []string{}))
+
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "client.internal", Organization: []string{"Mesh"}},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	// Unverified certificates do not satisfy the requirement
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+	res = httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	_, res = executeRequest(api, http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestSecurityTypedAccessors(t *testing.T) {
+	type User struct {
+		ID string
+	}
+
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithSecurityValidator("bearer", echopen.SecurityValidatorFunc(func(c echo.Context, cred *echopen.Credential) (interface{}, []string, error) {
+			return &User{ID: cred.Value}, []string{"read"}, nil
+		})),
+	)
+	api.Spec.GetComponents().AddSecurityScheme("api_key", &v320.SecurityScheme{
+		Type: v320.APIKeySecuritySchemeType,
+		In:   "header",
+		Name: "X-API-Key",
+	})
+	api.Spec.GetComponents().AddSecurityScheme("bearer", &v320.SecurityScheme{
+		Type:   v320.HTTPSecuritySchemeType,
+		Scheme: "bearer",
+	})
+
+	api.GET(
+		"/",
+		func(c echo.Context) error {
+			user, ok := echopen.Principal[*User](c)
+			assert.True(t, ok)
+			assert.Equal(t, "user-1", user.ID)
+
+			_, ok = echopen.Principal[string](c)
+			assert.False(t, ok)
+
+			auth, ok := echopen.SecurityValue(c, "api_key")
+			assert.True(t, ok)
+			assert.Equal(t, v320.APIKeySecuritySchemeType, auth.Type)
+			assert.Equal(t, "key", auth.Credential.Value)
+			assert.Nil(t, auth.Principal)
+
+			auth, ok = echopen.SecurityValue(c, "bearer")
+			assert.True(t, ok)
+			assert.Equal(t, []string{"read"}, auth.Scopes)
+
+			_, ok = echopen.SecurityValue(c, "unknown")
+			assert.False(t, ok)
+
+			return c.NoContent(http.StatusOK)
+		},
+		echopen.WithSecurityRequirementObject(&v320.SecurityRequirement{
+			"api_key": {},
+			"bearer":  {"read"},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "key")
+	req.Header.Set("Authorization", "Bearer user-1")
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestSecuritySchemeBuilders(t *testing.T) {
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithAPIKeyScheme("api_key", "header", "X-API-Key"),
+		echopen.WithBearerScheme("bearer", "JWT"),
+		echopen.WithBasicScheme("basic"),
+		echopen.WithOpenIDConnectScheme("oidc", "https://issuer.example.com/.well-known/openid-configuration"),
+		echopen.WithMutualTLSScheme("mtls"),
+		echopen.WithOAuth2Scheme("oauth", &v320.OAuthFlows{
+			AuthorizationCode: &v320.OAuthFlow{
+				AuthorizationURL: "https://issuer.example.com/authorize",
+				TokenURL:         "https://issuer.example.com/token",
+				Scopes:           map[string]string{"read": "Read access"},
+			},
+		}),
+		echopen.WithSecurityValidator("oauth", echopen.SecurityValidatorFunc(
+			func(c echo.Context, cred *echopen.Credential) (interface{}, []string, error) {
+				return cred.Value, []string{"read"}, nil
+			},
+		)),
+		echopen.WithBearerVerifier("bearer", func(c echo.Context, token string) (interface{}, []string, error) {
+			return token, nil, nil
+		}),
+	)
+
+	components := api.Spec.GetComponents()
+	assert.Equal(t, "X-API-Key", components.GetSecurityScheme("api_key").Name)
+	assert.Equal(t, "JWT", components.GetSecurityScheme("bearer").BearerFormat)
+	assert.Equal(t, "basic", components.GetSecurityScheme("basic").Scheme)
+	assert.Equal(t, v320.OpenIDConnectSecuritySchemeType, components.GetSecurityScheme("oidc").Type)
+	assert.Equal(t, v320.MutualTLSSecuritySchemeType, components.GetSecurityScheme("mtls").Type)
+
+	assert.NotPanics(t, func() {
+		api.GET("/", nil, echopen.WithSecurityRequirement("oauth", []string{"read"}))
+	})
+	assert.PanicsWithValue(t, "echopen: scope 'write' not declared by security scheme 'oauth'", func() {
+		api.GET("/write", nil, echopen.WithSecurityRequirement("oauth", []string{"write"}))
+	})
+	assert.Panics(t, func() {
+		echopen.WithSpecSecurityRequirement("oauth", []string{"write"})(api)
+	})
+
+	// Scopes are only checked against oauth2 flows
+	assert.NotPanics(t, func() {
+		api.GET("/admin", nil, echopen.WithSecurityRequirement("bearer", []string{"admin"}))
+	})
+
+	// Scopes can only be enforced by a registered validator
+	assert.PanicsWithValue(t, "echopen: security requirement for scheme 'api_key' lists scopes but no validator is registered", func() {
+		api.GET("/key", nil, echopen.WithSecurityRequirement("api_key", []string{"read"}))
+	})
+	// Tokens for oauth2 and openIdConnect schemes must be verified
+	assert.PanicsWithValue(t, "echopen: security scheme 'oidc' requires a validator to verify credentials", func() {
+		api.GET("/oidc", nil, echopen.WithSecurityRequirement("oidc", []string{}))
+	})
+}
+
+func TestSecurityCredentialWithoutValidator(t *testing.T) {
+	type tcd struct {
+		Name      string
+		Config    echopen.WrapperConfigFunc
+		Request   func(req *http.Request)
+		Challenge string
+	}
+
+	defs := []tcd{
+		{
+			"oidc",
+			echopen.WithOpenIDConnectScheme("scheme", "https://issuer.example.com/.well-known/openid-configuration"),
+			func(req *http.Request) { req.Header.Set("Authorization", "Bearer anything") },
+			`Bearer realm="Test", error="invalid_token"`,
+		},
+		{
+			"bearer",
+			echopen.WithBearerScheme("scheme", "JWT"),
+			func(req *http.Request) { req.Header.Set("Authorization", "Bearer garbage") },
+			`Bearer realm="Test", error="invalid_token"`,
+		},
+		{
+			"basic",
+			echopen.WithBasicScheme("scheme"),
+			func(req *http.Request) { req.SetBasicAuth("anyone", "wrong") },
+			`Basic realm="Test", charset="UTF-8"`,
+		},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			api := echopen.New("Test", "1.0.0", tc.Config)
+
+			assert.PanicsWithValue(t, "echopen: security scheme 'scheme' requires a validator to verify credentials", func() {
+				api.GET("/secure", nil, echopen.WithSecurityRequirement("scheme", nil))
+			})
+
+			api.GET("/", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			// Spec requirements added after the route are rejected at request time
+			echopen.WithSpecSecurityRequirement("scheme", []string{})(api)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tc.Request(req)
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+			assert.Equal(t, tc.Challenge, res.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestSecuritySchemeBuildersInvalid(t *testing.T) {
+	type tcd struct {
+		Name   string
+		Config echopen.WrapperConfigFunc
+	}
+
+	defs := []tcd{
+		{"api_key_no_name", echopen.WithAPIKeyScheme("s", "header", "")},
+		{"api_key_bad_location", echopen.WithAPIKeyScheme("s", "body", "key")},
+		{"http_no_scheme", echopen.WithSecurityScheme("s", &v320.SecurityScheme{Type: v320.HTTPSecuritySchemeType})},
+		{"oidc_no_url", echopen.WithOpenIDConnectScheme("s", "")},
+		{"oidc_bad_url", echopen.WithOpenIDConnectScheme("s", "ftp://issuer.example.com")},
+		{"oauth_no_flows", echopen.WithOAuth2Scheme("s", nil)},
+		{"oauth_empty_flows", echopen.WithOAuth2Scheme("s", &v320.OAuthFlows{})},
+		{"oauth_implicit_no_auth_url", echopen.WithOAuth2Scheme("s", &v320.OAuthFlows{
+			Implicit: &v320.OAuthFlow{Scopes: map[string]string{}},
+		})},
+		{"oauth_client_no_token_url", echopen.WithOAuth2Scheme("s", &v320.OAuthFlows{
+			ClientCredentials: &v320.OAuthFlow{Scopes: map[string]string{}},
+		})},
+		{"oauth_code_no_token_url", echopen.WithOAuth2Scheme("s", &v320.OAuthFlows{
+			AuthorizationCode: &v320.OAuthFlow{AuthorizationURL: "https://issuer.example.com/authorize"},
+		})},
+		{"oauth_bad_scope", echopen.WithOAuth2Scheme("s", &v320.OAuthFlows{
+			Password: &v320.OAuthFlow{TokenURL: "https://issuer.example.com/token", Scopes: map[string]string{"read write": ""}},
+		})},
+		{"unknown_type", echopen.WithSecurityScheme("s", &v320.SecurityScheme{Type: "other"})},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Panics(t, func() {
+				echopen.New("Test", "1.0.0", tc.Config)
+			})
+		})
+	}
+}
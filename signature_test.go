@@ -0,0 +1,153 @@
+package echopen_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anteo/echopen/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACSignatureScheme(t *testing.T) {
+	secret := []byte("partner-secret")
+
+	api := echopen.New(
+		"Test",
+		"1.0.0",
+		echopen.WithHMACSignatureScheme("signature", &echopen.HMACSignatureConfig{
+			Secret: func(c echo.Context) ([]byte, error) {
+				return secret, nil
+			},
+		}),
+	)
+
+	type Body struct {
+		Event string `json:"event"`
+	}
+
+	api.POST(
+		"/webhook",
+		func(c echo.Context) error {
+			assert.Equal(t, "created", c.Get("body").(*Body).Event)
+			return c.NoContent(http.StatusNoContent)
+		},
+		echopen.WithSecurityRequirement("signature", []string{}),
+		echopen.WithRequestBodyStruct(echo.MIMEApplicationJSON, "Event", Body{}),
+	)
+
+	sign := func(ts int64, body string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(fmt.Sprintf("%d.%s", ts, body)))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	body := `{"event":"created"}`
+	now := time.Now().Unix()
+
+	type tcd struct {
+		Name      string
+		Timestamp int64
+		Signature string
+		Expected  int
+	}
+
+	defs := []tcd{
+		{"valid", now, sign(now, body), http.StatusNoContent},
+		{"tampered", now, sign(now, `{"event":"deleted"}`), http.StatusUnauthorized},
+		{"replayed", now - 3600, sign(now-3600, body), http.StatusUnauthorized},
+		{"wrong_algorithm", now, strings.Replace(sign(now, body), "sha256", "sha512", 1), http.StatusUnauthorized},
+		{"missing", now, "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range defs {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+			req.Header.Set("X-Timestamp", fmt.Sprint(tc.Timestamp))
+			if tc.Signature != "" {
+				req.Header.Set("X-Signature", tc.Signature)
+			}
+			res := httptest.NewRecorder()
+			api.Engine.ServeHTTP(res, req)
+			assert.Equal(t, tc.Expected, res.Code)
+		})
+	}
+
+	buf, err := json.Marshal(api.Spec.Copy())
+	assert.NoError(t, err)
+	assert.Contains(t, string(buf), `"signature":{"type":"apiKey","in":"header","name":"X-Signature","x-signature":{"algorithm":"hmac-sha256"`)
+}
+
+func TestHMACSignatureMaxBodySize(t *testing.T) {
+	secret := []byte("partner-secret")
+	v := &echopen.HMACSignatureValidator{Config: &echopen.HMACSignatureConfig{
+		Algorithm:       "sha256",
+		TimestampHeader: "X-Timestamp",
+		Tolerance:       time.Minute,
+		MaxBodySize:     8,
+		Secret: func(c echo.Context) ([]byte, error) {
+			return secret, nil
+		},
+	}}
+
+	ts := fmt.Sprint(time.Now().Unix())
+	validate := func(body string, contentLength int64) error {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(ts + "." + body))
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.ContentLength = contentLength
+		req.Header.Set("X-Timestamp", ts)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		_, _, err := v.Validate(c, &echopen.Credential{Value: "sha256=" + hex.EncodeToString(mac.Sum(nil))})
+		return err
+	}
+
+	assert.NoError(t, validate("12345678", 8))
+	assert.ErrorIs(t, validate("123456789", 9), echopen.ErrRequestTooLarge)
+	// Bodies of unknown length are only read up to the limit
+	assert.ErrorIs(t, validate("123456789", -1), echopen.ErrRequestTooLarge)
+}
+
+func TestHMACSignatureTooLarge(t *testing.T) {
+	secret := []byte("partner-secret")
+	cfg := &echopen.HMACSignatureConfig{
+		MaxBodySize: 8,
+		Secret: func(c echo.Context) ([]byte, error) {
+			return secret, nil
+		},
+	}
+
+	api := echopen.New("Test", "1.0.0", echopen.WithHMACSignatureScheme("signature", cfg))
+
+	// Defaults are not written back to the caller's config
+	assert.Empty(t, cfg.Header)
+	assert.Empty(t, cfg.Algorithm)
+	assert.Zero(t, cfg.Tolerance)
+	assert.Equal(t, int64(8), cfg.MaxBodySize)
+
+	api.POST("/webhook", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, echopen.WithSecurityRequirement("signature", []string{}))
+
+	body := "123456789"
+	ts := fmt.Sprint(time.Now().Unix())
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "." + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Timestamp", ts)
+	req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	res := httptest.NewRecorder()
+	api.Engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+}
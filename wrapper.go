@@ -1,6 +1,8 @@
 package echopen
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,7 +27,10 @@ type APIWrapper struct {
 	Engine *echo.Echo
 	Config *Config
 
-	schemaMap map[reflect.Type]string
+	schemaMap          map[reflect.Type]string
+	securityValidators map[string]SecurityValidator
+	bodyDecoders       map[string]BodyDecoder
+	formatConverters   map[v320.SchemaFormat]FormatConverter
 }
 
 func New(title string, apiVersion string, config ...WrapperConfigFunc) *APIWrapper {
@@ -34,7 +39,10 @@ func New(title string, apiVersion string, config ...WrapperConfigFunc) *APIWrapp
 		Engine: echo.New(),
 		Config: &Config{},
 
-		schemaMap: map[reflect.Type]string{},
+		schemaMap:          map[reflect.Type]string{},
+		securityValidators: map[string]SecurityValidator{},
+		bodyDecoders:       map[string]BodyDecoder{},
+		formatConverters:   map[v320.SchemaFormat]FormatConverter{},
 	}
 
 	wrapper.Spec.Info.Title = title
@@ -178,6 +186,33 @@ func (w *APIWrapper) Start(addr string) error {
 	return w.Engine.Start(addr)
 }
 
+// StartTLS starts an HTTPS server using the given certificate and key files
+func (w *APIWrapper) StartTLS(addr string, certFile string, keyFile string) error {
+	return w.Engine.StartTLS(addr, certFile, keyFile)
+}
+
+// StartMutualTLS starts an HTTPS server that requests client certificates, verifying them against clientCAs.
+// Client certificates are optional at the TLS layer so that mutualTLS security requirements can be enforced per route.
+func (w *APIWrapper) StartMutualTLS(addr string, certFile string, keyFile string, clientCAs *x509.CertPool) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	s := w.Engine.TLSServer
+	s.Addr = addr
+	s.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+	if !w.Engine.DisableHTTP2 {
+		s.TLSConfig.NextProtos = append(s.TLSConfig.NextProtos, "h2")
+	}
+
+	return w.Engine.StartServer(s)
+}
+
 // Register a new route with the given method and path
 func (w *APIWrapper) Add(method string, path string, handler echo.HandlerFunc, config ...RouteConfigFunc) *RouteWrapper {
 	// Construct a new operation for this path and method
@@ -239,6 +274,7 @@ func (w *APIWrapper) Add(method string, path string, handler echo.HandlerFunc, c
 	// Add validation middleware to the start of the chain
 	middlewares := []echo.MiddlewareFunc{}
 	if !w.Config.DisableDefaultMiddleware {
+		wrapper.checkSecurityValidators()
 		middlewares = append(middlewares, wrapper.middleware())
 	}
 	middlewares = append(middlewares, wrapper.Middlewares...)
@@ -305,11 +341,30 @@ func (w *APIWrapper) TRACE(path string, handler echo.HandlerFunc, config ...Rout
 
 // Extend the default echo handler to cover errors defined by echopen
 func DefaultErrorHandler(err error, c echo.Context) {
-	if errors.Is(err, ErrSecurityRequirementsNotMet) {
+	var se *SecurityError
+	if errors.As(err, &se) {
+		for _, ch := range se.Challenges {
+			c.Response().Header().Add(echo.HeaderWWWAuthenticate, ch)
+		}
+	}
+
+	var pe *ParameterError
+	if errors.As(err, &pe) {
+		c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message":   http.StatusText(http.StatusBadRequest),
+			"parameter": pe.Name,
+			"in":        pe.In,
+			"keyword":   pe.Keyword,
+		})
+	} else if errors.Is(err, ErrSecurityRequirementsNotMet) {
 		c.JSON(http.StatusUnauthorized, map[string]interface{}{
 			"message": http.StatusText(http.StatusUnauthorized),
 		})
-	} else if errors.Is(err, ErrRequiredParameterMissing) {
+	} else if errors.Is(err, ErrInsufficientScope) {
+		c.JSON(http.StatusForbidden, map[string]interface{}{
+			"message": http.StatusText(http.StatusForbidden),
+		})
+	} else if errors.Is(err, ErrRequiredParameterMissing) || errors.Is(err, ErrParameterInvalid) || errors.Is(err, ErrRequestBodyMissing) || errors.Is(err, ErrRequestBodyInvalid) {
 		c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": http.StatusText(http.StatusBadRequest),
 		})
@@ -317,6 +372,10 @@ func DefaultErrorHandler(err error, c echo.Context) {
 		c.JSON(http.StatusUnsupportedMediaType, map[string]interface{}{
 			"message": http.StatusText(http.StatusUnsupportedMediaType),
 		})
+	} else if errors.Is(err, ErrRequestTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, map[string]interface{}{
+			"message": http.StatusText(http.StatusRequestEntityTooLarge),
+		})
 	} else if he, ok := err.(*echo.HTTPError); ok {
 		if c.Echo().Debug && he.Internal != nil {
 			c.JSON(he.Code, map[string]interface{}{
@@ -93,3 +93,66 @@ func WithBaseURL(baseURL string) WrapperConfigFunc {
 		return a
 	}
 }
+
+// AddSpecSecurityRequirement adds a top level security requirement that applies to all operations without their own requirements.
+// The security scheme must be registered first.
+func (a *APIWrapper) AddSpecSecurityRequirement(req *v320.SecurityRequirement) {
+	a.validateSecurityRequirement(req)
+	a.Spec.AddSecurityRequirement(req)
+}
+
+func WithSpecSecurityRequirement(name string, scopes []string) WrapperConfigFunc {
+	return func(a *APIWrapper) *APIWrapper {
+		a.AddSpecSecurityRequirement(&v320.SecurityRequirement{name: scopes})
+		return a
+	}
+}
+
+func (a *APIWrapper) SetSecurityValidator(name string, v SecurityValidator) {
+	a.securityValidators[name] = v
+}
+
+// WithSecurityValidator registers a validator for credentials supplied for the named security scheme.
+// Requests are rejected if the validator fails, or if the granted scopes do not cover the requirement.
+func WithSecurityValidator(name string, v SecurityValidator) WrapperConfigFunc {
+	return func(a *APIWrapper) *APIWrapper {
+		a.SetSecurityValidator(name, v)
+		return a
+	}
+}
+
+// WithBearerVerifier registers a function to verify tokens supplied for the named http bearer security scheme
+func WithBearerVerifier(name string, f BearerVerifierFunc) WrapperConfigFunc {
+	return WithSecurityValidator(name, f)
+}
+
+// WithBasicVerifier registers a function to verify credentials supplied for the named http basic security scheme
+func WithBasicVerifier(name string, f BasicVerifierFunc) WrapperConfigFunc {
+	return WithSecurityValidator(name, f)
+}
+
+func (a *APIWrapper) SetBodyDecoder(mime string, d BodyDecoder) {
+	a.bodyDecoders[mime] = d
+}
+
+// WithBodyDecoder registers a decoder for request bodies of the given media type, such as YAML or MessagePack.
+// Registered decoders take precedence over the built in JSON, XML, and form decoders.
+func WithBodyDecoder(mime string, d BodyDecoder) WrapperConfigFunc {
+	return func(a *APIWrapper) *APIWrapper {
+		a.SetBodyDecoder(mime, d)
+		return a
+	}
+}
+
+func (a *APIWrapper) SetFormatConverter(format v320.SchemaFormat, fn FormatConverter) {
+	a.formatConverters[format] = fn
+}
+
+// WithFormatConverter registers a converter for parameter values of the given schema format, such as decimal.
+// Registered converters take precedence over the built in conversion of the format.
+func WithFormatConverter(format v320.SchemaFormat, fn FormatConverter) WrapperConfigFunc {
+	return func(a *APIWrapper) *APIWrapper {
+		a.SetFormatConverter(format, fn)
+		return a
+	}
+}